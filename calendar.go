@@ -0,0 +1,362 @@
+package timing
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// calendarSearchLimit bounds the days searched for the next open time, so
+// that a calendar that is never open panics rather than hangs.
+const calendarSearchLimit = 10 * 366
+
+// Window is a span of time from From up to, but excluding, To.
+type Window struct {
+	From time.Time
+	To   time.Time
+}
+
+func (w Window) contains(t time.Time) bool {
+	return !t.Before(w.From) && t.Before(w.To)
+}
+
+// Calendar knows which days are business days, by closed weekdays and
+// holidays, and when business is suspended for blackout windows, e.g. for
+// maintenance. Days are taken in the calendar's location.
+type Calendar struct {
+	location *time.Location
+
+	mu             sync.RWMutex
+	closedWeekdays [7]bool
+	holidays       map[string]string
+	blackouts      []Window
+}
+
+// NewCalendar returns a calendar without closed days, in location, or in UTC
+// if location is nil.
+func NewCalendar(location *time.Location) *Calendar {
+	if location == nil {
+		location = time.UTC
+	}
+
+	return &Calendar{
+		location: location,
+		holidays: make(map[string]string),
+	}
+}
+
+func (c *Calendar) Location() *time.Location {
+	return c.location
+}
+
+// CloseWeekdays closes the given weekdays every week.
+func (c *Calendar) CloseWeekdays(weekdays ...time.Weekday) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, weekday := range weekdays {
+		c.closedWeekdays[weekday] = true
+	}
+}
+
+// AddHoliday closes the day of date, which is read in the calendar's
+// location.
+func (c *Calendar) AddHoliday(date time.Time, name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.holidays[c.dateKey(date)] = name
+}
+
+// Holiday returns the name of the holiday on the day of t, if any.
+func (c *Calendar) Holiday(t time.Time) (name string, ok bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	name, ok = c.holidays[c.dateKey(t)]
+	return name, ok
+}
+
+// AddBlackout suspends business from from up to to.
+func (c *Calendar) AddBlackout(from, to time.Time) {
+	if !to.After(from) {
+		panic("to must be after from")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.blackouts = append(c.blackouts, Window{From: from, To: to})
+}
+
+func (c *Calendar) dateKey(t time.Time) string {
+	return t.In(c.location).Format(time.DateOnly)
+}
+
+// IsBusinessDay reports whether the day of t is neither a closed weekday nor
+// a holiday. Blackouts don't matter.
+func (c *Calendar) IsBusinessDay(t time.Time) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.isBusinessDay(t)
+}
+
+func (c *Calendar) isBusinessDay(t time.Time) bool {
+	if c.closedWeekdays[t.In(c.location).Weekday()] {
+		return false
+	}
+
+	_, isHoliday := c.holidays[c.dateKey(t)]
+
+	return !isHoliday
+}
+
+// IsOpen reports whether t is on a business day and outside of all blackouts.
+func (c *Calendar) IsOpen(t time.Time) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.isBusinessDay(t) && c.blackoutIndex(t) < 0
+}
+
+// NextOpen returns t if the calendar is open at t, and otherwise the next
+// time it opens: the start of the next business day, or the end of a
+// blackout.
+func (c *Calendar) NextOpen(t time.Time) time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for range calendarSearchLimit {
+		if !c.isBusinessDay(t) {
+			t = c.startOfDay(t).AddDate(0, 0, 1)
+			continue
+		}
+
+		if blackoutIndex := c.blackoutIndex(t); blackoutIndex >= 0 {
+			t = c.blackouts[blackoutIndex].To
+			continue
+		}
+
+		return t
+	}
+
+	panic("calendar is never open")
+}
+
+// ShiftToOpen returns t if the calendar is open at t. Otherwise it moves t to
+// the same time of day on the next business day, or to the end of a blackout,
+// until the calendar is open.
+func (c *Calendar) ShiftToOpen(t time.Time) time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	location := t.Location()
+	for range calendarSearchLimit {
+		if !c.isBusinessDay(t) {
+			t = t.In(c.location).AddDate(0, 0, 1).In(location)
+			continue
+		}
+
+		if blackoutIndex := c.blackoutIndex(t); blackoutIndex >= 0 {
+			t = c.blackouts[blackoutIndex].To
+			continue
+		}
+
+		return t
+	}
+
+	panic("calendar is never open")
+}
+
+func (c *Calendar) blackoutIndex(t time.Time) int {
+	return slices.IndexFunc(c.blackouts, func(w Window) bool { return w.contains(t) })
+}
+
+func (c *Calendar) startOfDay(t time.Time) time.Time {
+	year, month, day := t.In(c.location).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, c.location)
+}
+
+// AddBusinessDays moves t by n business days, keeping its time of day. A
+// negative n moves backwards.
+func (c *Calendar) AddBusinessDays(t time.Time, n int) time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+
+	t = t.In(c.location)
+	for searched := 0; n > 0; searched++ {
+		if searched == calendarSearchLimit {
+			panic("calendar is never open")
+		}
+
+		t = t.AddDate(0, 0, step)
+		if c.isBusinessDay(t) {
+			n--
+			searched = 0
+		}
+	}
+
+	return t
+}
+
+// BusinessDaysBetween counts the business days from the day of from up to,
+// but excluding, the day of to. It is negative if to is before from.
+func (c *Calendar) BusinessDaysBetween(from, to time.Time) int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	sign := 1
+	if to.Before(from) {
+		sign, from, to = -1, to, from
+	}
+
+	count := 0
+	last := c.startOfDay(to)
+	for day := c.startOfDay(from); day.Before(last); day = day.AddDate(0, 0, 1) {
+		if c.isBusinessDay(day) {
+			count++
+		}
+	}
+
+	return sign * count
+}
+
+// LoadCalendar reads a calendar from lines like these:
+//
+//	# Comments and empty lines are ignored.
+//	location Europe/Berlin
+//	closed saturday sunday
+//	holiday 2024-12-25 Christmas Day
+//	2024-12-26
+//	blackout 2024-03-02T22:00:00Z 2024-03-03T02:00:00Z
+//
+// A bare date is a holiday without a name. The location, which defaults to
+// UTC, has to come before any holiday.
+func LoadCalendar(r io.Reader) (*Calendar, error) {
+	calendar := NewCalendar(time.UTC)
+	holidaysSeen := false
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		if err := calendar.parseLine(fields, &holidaysSeen); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return calendar, nil
+}
+
+// LoadCalendarFile reads a calendar from the file at path, see LoadCalendar.
+func LoadCalendarFile(path string) (*Calendar, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return LoadCalendar(file)
+}
+
+func (c *Calendar) parseLine(fields []string, holidaysSeen *bool) error {
+	switch directive := strings.ToLower(fields[0]); directive {
+	case "location":
+		if len(fields) != 2 {
+			return errors.New("location needs exactly one name")
+		}
+		if *holidaysSeen {
+			return errors.New("location must come before holidays")
+		}
+
+		location, err := time.LoadLocation(fields[1])
+		if err != nil {
+			return err
+		}
+		c.location = location
+	case "closed":
+		if len(fields) < 2 {
+			return errors.New("closed needs at least one weekday")
+		}
+
+		for _, name := range fields[1:] {
+			weekday, err := parseWeekday(name)
+			if err != nil {
+				return err
+			}
+			c.CloseWeekdays(weekday)
+		}
+	case "holiday":
+		if len(fields) < 2 {
+			return errors.New("holiday needs a date")
+		}
+
+		return c.parseHoliday(fields[1], strings.Join(fields[2:], " "), holidaysSeen)
+	case "blackout":
+		if len(fields) != 3 {
+			return errors.New("blackout needs a start and an end")
+		}
+
+		from, err := time.Parse(time.RFC3339, fields[1])
+		if err != nil {
+			return err
+		}
+		to, err := time.Parse(time.RFC3339, fields[2])
+		if err != nil {
+			return err
+		}
+		if !to.After(from) {
+			return errors.New("blackout must end after it starts")
+		}
+		c.AddBlackout(from, to)
+	default:
+		if len(fields) == 1 {
+			return c.parseHoliday(fields[0], "", holidaysSeen)
+		}
+
+		return fmt.Errorf("unknown directive %q", directive)
+	}
+
+	return nil
+}
+
+func (c *Calendar) parseHoliday(date, name string, holidaysSeen *bool) error {
+	day, err := time.ParseInLocation(time.DateOnly, date, c.location)
+	if err != nil {
+		return err
+	}
+
+	c.AddHoliday(day, name)
+	*holidaysSeen = true
+
+	return nil
+}
+
+func parseWeekday(name string) (time.Weekday, error) {
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		if strings.EqualFold(name, weekday.String()) || strings.EqualFold(name, weekday.String()[:3]) {
+			return weekday, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown weekday %q", name)
+}
@@ -0,0 +1,97 @@
+package timing
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// CalendarPolicy decides what happens to occurrences that fall on a time the
+// calendar is closed.
+type CalendarPolicy int
+
+const (
+	// SkipClosed drops such occurrences.
+	SkipClosed CalendarPolicy = iota
+	// ShiftToNextOpen performs them at the same time of day on the next
+	// business day, or at the end of the blackout they fall in, see
+	// Calendar.ShiftToOpen. E.g. a daily job's Saturday and Sunday occurrences
+	// both run on Monday at the job's usual time.
+	ShiftToNextOpen
+)
+
+// CalendarEventScheduler applies Calendar with Policy to all actions it
+// schedules on EventScheduler, which may be a system or a simulated one.
+// Whether an occurrence is due is decided by the time it was scheduled for.
+//
+// PerformNow and PerformAfter apply the calendar when scheduling. Repeated
+// actions are checked as each occurrence is performed, before the middlewares
+// installed in their context, but EventScheduler's own middlewares, metrics
+// and hooks see skipped occurrences as performed events.
+type CalendarEventScheduler struct {
+	EventScheduler
+	Calendar *Calendar
+	Policy   CalendarPolicy
+}
+
+func (c CalendarEventScheduler) PerformNow(action Action, ctx context.Context) {
+	c.PerformAfter(action, 0, ctx)
+}
+
+func (c CalendarEventScheduler) PerformAfter(action Action, duration time.Duration, ctx context.Context) {
+	if c.Calendar == nil {
+		panic("Calendar can't be nil")
+	}
+
+	now := c.EventScheduler.Now()
+	scheduledTime := now.Add(duration)
+
+	if !c.Calendar.IsOpen(scheduledTime) {
+		if c.Policy != ShiftToNextOpen {
+			return
+		}
+
+		duration = c.Calendar.ShiftToOpen(scheduledTime).Sub(now)
+	}
+
+	c.EventScheduler.PerformAfter(action, duration, c.withCalendar(action, ctx))
+}
+
+func (c CalendarEventScheduler) PerformRepeatedly(action Action, until *time.Time, interval time.Duration, ctx context.Context) {
+	c.EventScheduler.PerformRepeatedly(action, until, interval, c.withCalendar(action, ctx))
+}
+
+// withCalendar installs the calendar as a middleware rather than wrapping the
+// action, so that schedulers still see the action's own type. Like a
+// scheduler's own middleware, it goes outside of those already in ctx, and
+// occurrences shifted to the next open time are scheduled again with action
+// and ctx as they were passed in.
+func (c CalendarEventScheduler) withCalendar(action Action, ctx context.Context) context.Context {
+	if c.Calendar == nil {
+		panic("Calendar can't be nil")
+	}
+
+	calendar := func(wrapped Action) Action {
+		return ActionFunc(func(actionContext ActionContext) {
+			scheduledTime := actionContext.Metadata().ScheduledTime
+
+			if c.Calendar.IsOpen(scheduledTime) {
+				wrapped.Perform(actionContext)
+				return
+			}
+
+			if c.Policy == ShiftToNextOpen {
+				c.PerformAfter(action, c.Calendar.ShiftToOpen(scheduledTime).Sub(actionContext.Clock().Now()), ctx)
+			}
+
+			actionContext.DoneSchedulingNewEvents()
+		})
+	}
+
+	calendarCtx := context.WithValue(ctx, middlewareKey{}, slices.Concat([]ActionMiddleware{calendar}, MiddlewareFrom(ctx)))
+	if SchedulerFrom(calendarCtx) != nil {
+		return calendarCtx
+	}
+
+	return WithScheduler(calendarCtx, c)
+}
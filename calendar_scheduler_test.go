@@ -0,0 +1,174 @@
+package timing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// scheduledActionContext is an ActionContext for an action scheduled at
+// scheduledTime and performed at the clock's time.
+type scheduledActionContext struct {
+	context.Context
+	clock                   Clock
+	scheduledTime           time.Time
+	doneSchedulingNewEvents bool
+}
+
+func (s *scheduledActionContext) Clock() Clock { return s.clock }
+
+func (s *scheduledActionContext) DoneSchedulingNewEvents() { s.doneSchedulingNewEvents = true }
+
+func (s *scheduledActionContext) Metadata() EventMetadata {
+	return EventMetadata{ScheduledTime: s.scheduledTime}
+}
+
+func (s *scheduledActionContext) ScheduledTime() time.Time { return s.scheduledTime }
+
+func (s *scheduledActionContext) EventID() uint64 { return 0 }
+
+func (s *scheduledActionContext) JobName() string { return "" }
+
+func (s *scheduledActionContext) Occurrence() int { return 0 }
+
+func (s *scheduledActionContext) Scheduler() EventScheduler { return nil }
+
+func TestCalendarEventScheduler(t *testing.T) {
+	t.Parallel()
+
+	calendar := NewCalendar(nil)
+	calendar.CloseWeekdays(time.Saturday, time.Sunday)
+
+	friday := time.Date(2024, 12, 27, 12, 0, 0, 0, time.UTC)
+	saturday := friday.AddDate(0, 0, 1)
+	monday := time.Date(2024, 12, 30, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		policy          CalendarPolicy
+		scheduledTime   time.Time
+		wantPerformed   bool
+		wantRescheduled *time.Duration
+	}{
+		{name: "open, skip", policy: SkipClosed, scheduledTime: friday, wantPerformed: true},
+		{name: "open, shift", policy: ShiftToNextOpen, scheduledTime: friday, wantPerformed: true},
+		{name: "closed, skip", policy: SkipClosed, scheduledTime: saturday},
+		{name: "closed, shift", policy: ShiftToNextOpen, scheduledTime: saturday, wantRescheduled: ptr(monday.Sub(saturday))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			pending := &pendingEventScheduler{now: tt.scheduledTime}
+			eventScheduler := CalendarEventScheduler{
+				EventScheduler: pending,
+				Calendar:       calendar,
+				Policy:         tt.policy,
+			}
+
+			performed := false
+			action := ActionFunc(func(ActionContext) { performed = true })
+
+			wrapped := 0
+			counting := func(action Action) Action {
+				return ActionFunc(func(ctx ActionContext) {
+					wrapped++
+					action.Perform(ctx)
+				})
+			}
+
+			eventScheduler.PerformRepeatedly(action, nil, time.Hour, WithMiddleware(context.Background(), counting))
+			require.Len(t, pending.pending, 1)
+
+			scheduled := pending.pending[0]
+			pending.pending = nil
+
+			ctx := &scheduledActionContext{Context: scheduled.ctx, clock: pending, scheduledTime: tt.scheduledTime}
+			Chain(scheduled.action, MiddlewareFrom(scheduled.ctx)...).Perform(ctx)
+
+			require.Equal(t, tt.wantPerformed, performed)
+			require.Equal(t, !tt.wantPerformed, ctx.doneSchedulingNewEvents)
+			require.Equal(t, performed, wrapped == 1)
+
+			if tt.wantRescheduled == nil {
+				require.Empty(t, pending.pending)
+				return
+			}
+
+			require.Len(t, pending.pending, 1)
+			require.Equal(t, *tt.wantRescheduled, pending.pending[0].duration)
+			require.Len(t, MiddlewareFrom(pending.pending[0].ctx), 2)
+			require.Equal(t, eventScheduler, SchedulerFrom(pending.pending[0].ctx))
+
+			rescheduled := pending.pending[0]
+			ctx = &scheduledActionContext{Context: rescheduled.ctx, clock: pending, scheduledTime: monday}
+			Chain(rescheduled.action, MiddlewareFrom(rescheduled.ctx)...).Perform(ctx)
+			require.True(t, performed)
+			require.Equal(t, 1, wrapped)
+		})
+	}
+}
+
+func TestCalendarEventScheduler_oneOff(t *testing.T) {
+	t.Parallel()
+
+	calendar := NewCalendar(nil)
+	calendar.CloseWeekdays(time.Saturday, time.Sunday)
+
+	friday := time.Date(2024, 12, 27, 12, 0, 0, 0, time.UTC)
+	saturday := friday.AddDate(0, 0, 1)
+	monday := time.Date(2024, 12, 30, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		policy        CalendarPolicy
+		now           time.Time
+		duration      time.Duration
+		wantScheduled *time.Duration
+	}{
+		{name: "open", policy: SkipClosed, now: friday, duration: time.Hour, wantScheduled: ptr(time.Hour)},
+		{name: "closed now, skip", policy: SkipClosed, now: saturday},
+		{name: "closed now, shift", policy: ShiftToNextOpen, now: saturday, wantScheduled: ptr(monday.Sub(saturday))},
+		{name: "closed later, skip", policy: SkipClosed, now: friday, duration: 24 * time.Hour},
+		{name: "closed later, shift", policy: ShiftToNextOpen, now: friday, duration: 24 * time.Hour, wantScheduled: ptr(monday.Sub(friday))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			pending := &pendingEventScheduler{now: tt.now}
+			eventScheduler := CalendarEventScheduler{
+				EventScheduler: pending,
+				Calendar:       calendar,
+				Policy:         tt.policy,
+			}
+
+			action := ActionFunc(func(ActionContext) {})
+			if tt.duration == 0 {
+				eventScheduler.PerformNow(action, context.Background())
+			} else {
+				eventScheduler.PerformAfter(action, tt.duration, context.Background())
+			}
+
+			if tt.wantScheduled == nil {
+				require.Empty(t, pending.pending)
+				return
+			}
+
+			require.Len(t, pending.pending, 1)
+			require.Equal(t, *tt.wantScheduled, pending.pending[0].duration)
+		})
+	}
+}
+
+func TestCalendarEventScheduler_nilCalendar(t *testing.T) {
+	t.Parallel()
+
+	eventScheduler := CalendarEventScheduler{EventScheduler: &pendingEventScheduler{}}
+
+	require.PanicsWithValue(t, "Calendar can't be nil", func() {
+		eventScheduler.PerformNow(ActionFunc(func(ActionContext) {}), context.Background())
+	})
+}
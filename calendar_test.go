@@ -0,0 +1,328 @@
+package timing
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newTestCalendar returns a calendar closed on weekends and on
+// 2024-12-25, with a blackout on the morning of 2024-12-27.
+func newTestCalendar() *Calendar {
+	calendar := NewCalendar(nil)
+	calendar.CloseWeekdays(time.Saturday, time.Sunday)
+	calendar.AddHoliday(time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC), "Christmas Day")
+	calendar.AddBlackout(
+		time.Date(2024, 12, 27, 6, 0, 0, 0, time.UTC),
+		time.Date(2024, 12, 27, 10, 0, 0, 0, time.UTC),
+	)
+
+	return calendar
+}
+
+func TestCalendar_IsBusinessDay(t *testing.T) {
+	t.Parallel()
+
+	calendar := newTestCalendar()
+
+	tests := []struct {
+		name string
+		day  time.Time
+		want bool
+	}{
+		{name: "weekday", day: time.Date(2024, 12, 24, 12, 0, 0, 0, time.UTC), want: true},
+		{name: "holiday", day: time.Date(2024, 12, 25, 12, 0, 0, 0, time.UTC), want: false},
+		{name: "saturday", day: time.Date(2024, 12, 28, 12, 0, 0, 0, time.UTC), want: false},
+		{name: "sunday", day: time.Date(2024, 12, 29, 12, 0, 0, 0, time.UTC), want: false},
+		{name: "blackout", day: time.Date(2024, 12, 27, 7, 0, 0, 0, time.UTC), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, calendar.IsBusinessDay(tt.day))
+		})
+	}
+}
+
+func TestCalendar_Holiday(t *testing.T) {
+	t.Parallel()
+
+	calendar := newTestCalendar()
+
+	name, ok := calendar.Holiday(time.Date(2024, 12, 25, 23, 59, 0, 0, time.UTC))
+	require.True(t, ok)
+	require.Equal(t, "Christmas Day", name)
+
+	_, ok = calendar.Holiday(time.Date(2024, 12, 26, 0, 0, 0, 0, time.UTC))
+	require.False(t, ok)
+}
+
+func TestCalendar_location(t *testing.T) {
+	t.Parallel()
+
+	location := time.FixedZone("UTC+2", 2*60*60)
+	calendar := NewCalendar(location)
+	calendar.CloseWeekdays(time.Saturday, time.Sunday)
+
+	require.Equal(t, location, calendar.Location())
+	require.Equal(t, time.UTC, NewCalendar(nil).Location())
+
+	// Friday 23:00 UTC is already Saturday in the calendar's location.
+	friday := time.Date(2024, 12, 27, 23, 0, 0, 0, time.UTC)
+	require.False(t, calendar.IsBusinessDay(friday))
+	require.Equal(t, time.Date(2024, 12, 30, 0, 0, 0, 0, location), calendar.NextOpen(friday))
+}
+
+func TestCalendar_NextOpen(t *testing.T) {
+	t.Parallel()
+
+	calendar := newTestCalendar()
+
+	tests := []struct {
+		name string
+		t    time.Time
+		want time.Time
+	}{
+		{
+			name: "open",
+			t:    time.Date(2024, 12, 24, 12, 0, 0, 0, time.UTC),
+			want: time.Date(2024, 12, 24, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "holiday",
+			t:    time.Date(2024, 12, 25, 12, 0, 0, 0, time.UTC),
+			want: time.Date(2024, 12, 26, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "blackout",
+			t:    time.Date(2024, 12, 27, 6, 0, 0, 0, time.UTC),
+			want: time.Date(2024, 12, 27, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "end of blackout",
+			t:    time.Date(2024, 12, 27, 10, 0, 0, 0, time.UTC),
+			want: time.Date(2024, 12, 27, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "weekend",
+			t:    time.Date(2024, 12, 28, 12, 0, 0, 0, time.UTC),
+			want: time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.True(t, tt.want.Equal(calendar.NextOpen(tt.t)))
+			require.Equal(t, tt.want.Equal(tt.t), calendar.IsOpen(tt.t))
+		})
+	}
+}
+
+func TestCalendar_NextOpen_blackoutUntilClosedDay(t *testing.T) {
+	t.Parallel()
+
+	calendar := newTestCalendar()
+	calendar.AddBlackout(
+		time.Date(2024, 12, 27, 20, 0, 0, 0, time.UTC),
+		time.Date(2024, 12, 28, 2, 0, 0, 0, time.UTC),
+	)
+
+	next := calendar.NextOpen(time.Date(2024, 12, 27, 21, 0, 0, 0, time.UTC))
+	require.True(t, time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC).Equal(next))
+}
+
+func TestCalendar_NextOpen_neverOpen(t *testing.T) {
+	t.Parallel()
+
+	calendar := NewCalendar(nil)
+	calendar.CloseWeekdays(time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday)
+
+	require.False(t, calendar.IsOpen(time.Time{}))
+	require.PanicsWithValue(t, "calendar is never open", func() { calendar.NextOpen(time.Time{}) })
+	require.PanicsWithValue(t, "calendar is never open", func() { calendar.ShiftToOpen(time.Time{}) })
+	require.PanicsWithValue(t, "calendar is never open", func() { calendar.AddBusinessDays(time.Time{}, 1) })
+}
+
+func TestCalendar_ShiftToOpen(t *testing.T) {
+	t.Parallel()
+
+	calendar := newTestCalendar()
+	calendar.AddBlackout(
+		time.Date(2024, 12, 27, 20, 0, 0, 0, time.UTC),
+		time.Date(2024, 12, 28, 2, 0, 0, 0, time.UTC),
+	)
+
+	tests := []struct {
+		name string
+		t    time.Time
+		want time.Time
+	}{
+		{
+			name: "open",
+			t:    time.Date(2024, 12, 24, 12, 0, 0, 0, time.UTC),
+			want: time.Date(2024, 12, 24, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "holiday",
+			t:    time.Date(2024, 12, 25, 12, 0, 0, 0, time.UTC),
+			want: time.Date(2024, 12, 26, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "blackout",
+			t:    time.Date(2024, 12, 27, 6, 0, 0, 0, time.UTC),
+			want: time.Date(2024, 12, 27, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "weekend",
+			t:    time.Date(2024, 12, 28, 12, 0, 0, 0, time.UTC),
+			want: time.Date(2024, 12, 30, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "blackout until closed day",
+			t:    time.Date(2024, 12, 27, 21, 0, 0, 0, time.UTC),
+			want: time.Date(2024, 12, 30, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "other location",
+			t:    time.Date(2024, 12, 28, 12, 0, 0, 0, time.FixedZone("UTC+2", 2*60*60)),
+			want: time.Date(2024, 12, 30, 12, 0, 0, 0, time.FixedZone("UTC+2", 2*60*60)),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := calendar.ShiftToOpen(tt.t)
+			require.True(t, tt.want.Equal(got))
+			require.Equal(t, tt.t.Location(), got.Location())
+			require.True(t, calendar.IsOpen(got))
+		})
+	}
+}
+
+func TestCalendar_AddBusinessDays(t *testing.T) {
+	t.Parallel()
+
+	calendar := newTestCalendar()
+	tuesday := time.Date(2024, 12, 24, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		n    int
+		want time.Time
+	}{
+		{name: "zero", n: 0, want: tuesday},
+		{name: "over holiday", n: 1, want: time.Date(2024, 12, 26, 9, 30, 0, 0, time.UTC)},
+		{name: "over weekend", n: 3, want: time.Date(2024, 12, 30, 9, 30, 0, 0, time.UTC)},
+		{name: "backwards", n: -2, want: time.Date(2024, 12, 20, 9, 30, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.True(t, tt.want.Equal(calendar.AddBusinessDays(tuesday, tt.n)))
+		})
+	}
+}
+
+func TestCalendar_BusinessDaysBetween(t *testing.T) {
+	t.Parallel()
+
+	calendar := newTestCalendar()
+	monday := time.Date(2024, 12, 23, 15, 0, 0, 0, time.UTC)
+	nextMonday := time.Date(2024, 12, 30, 9, 0, 0, 0, time.UTC)
+
+	require.Equal(t, 4, calendar.BusinessDaysBetween(monday, nextMonday))
+	require.Equal(t, -4, calendar.BusinessDaysBetween(nextMonday, monday))
+	require.Equal(t, 0, calendar.BusinessDaysBetween(monday, monday.Add(time.Hour)))
+}
+
+func TestCalendar_AddBlackout_invalid(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 12, 24, 0, 0, 0, 0, time.UTC)
+
+	require.PanicsWithValue(t, "to must be after from", func() { NewCalendar(nil).AddBlackout(now, now) })
+}
+
+const testCalendarFile = `# Test calendar
+location Europe/Berlin
+closed sat Sunday
+
+holiday 2024-12-25 Christmas Day
+2024-12-26
+blackout 2024-12-27T06:00:00Z 2024-12-27T10:00:00Z
+`
+
+func TestLoadCalendar(t *testing.T) {
+	t.Parallel()
+
+	calendar, err := LoadCalendar(strings.NewReader(testCalendarFile))
+	require.NoError(t, err)
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	require.Equal(t, berlin, calendar.Location())
+
+	name, ok := calendar.Holiday(time.Date(2024, 12, 25, 12, 0, 0, 0, berlin))
+	require.True(t, ok)
+	require.Equal(t, "Christmas Day", name)
+
+	name, ok = calendar.Holiday(time.Date(2024, 12, 26, 12, 0, 0, 0, berlin))
+	require.True(t, ok)
+	require.Empty(t, name)
+
+	require.False(t, calendar.IsBusinessDay(time.Date(2024, 12, 28, 12, 0, 0, 0, berlin)))
+	require.False(t, calendar.IsBusinessDay(time.Date(2024, 12, 29, 12, 0, 0, 0, berlin)))
+	require.False(t, calendar.IsOpen(time.Date(2024, 12, 27, 7, 0, 0, 0, time.UTC)))
+	require.True(t, calendar.IsOpen(time.Date(2024, 12, 27, 10, 0, 0, 0, time.UTC)))
+}
+
+func TestLoadCalendar_invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		file    string
+		wantErr string
+	}{
+		{name: "unknown directive", file: "open monday friday", wantErr: `line 1: unknown directive "open"`},
+		{name: "unknown weekday", file: "\nclosed someday", wantErr: `line 2: unknown weekday "someday"`},
+		{name: "missing weekday", file: "closed", wantErr: "line 1: closed needs at least one weekday"},
+		{name: "missing date", file: "holiday", wantErr: "line 1: holiday needs a date"},
+		{name: "invalid date", file: "2024-13-01", wantErr: "line 1: parsing time"},
+		{name: "location after holiday", file: "2024-12-25\nlocation UTC", wantErr: "line 2: location must come before holidays"},
+		{name: "unknown location", file: "location Nowhere/Special", wantErr: "line 1: unknown time zone"},
+		{name: "incomplete blackout", file: "blackout 2024-12-27T06:00:00Z", wantErr: "line 1: blackout needs a start and an end"},
+		{name: "reversed blackout", file: "blackout 2024-12-27T10:00:00Z 2024-12-27T06:00:00Z", wantErr: "line 1: blackout must end after it starts"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			calendar, err := LoadCalendar(strings.NewReader(tt.file))
+			require.Nil(t, calendar)
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestLoadCalendarFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "calendar.txt")
+	require.NoError(t, os.WriteFile(path, []byte(testCalendarFile), 0o600))
+
+	calendar, err := LoadCalendarFile(path)
+	require.NoError(t, err)
+	require.False(t, calendar.IsBusinessDay(time.Date(2024, 12, 25, 12, 0, 0, 0, time.UTC)))
+
+	_, err = LoadCalendarFile(filepath.Join(t.TempDir(), "missing.txt"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
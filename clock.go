@@ -0,0 +1,42 @@
+package timing
+
+import (
+	"context"
+	"time"
+)
+
+// ActionContextClockKey is answered with their clock by the schedulers'
+// action contexts.
+//
+// Deprecated: Use ClockFrom, which also finds clocks stored with WithClock.
+const ActionContextClockKey = "actionContextClock"
+
+type clockKey struct{}
+
+// WithClock returns a copy of ctx carrying clock, to be read with ClockFrom
+// wherever ctx is passed instead of threading clock through constructors. The
+// schedulers' action contexts carry their clock, so that ClockFrom finds it
+// in every context derived from them.
+func WithClock(ctx context.Context, clock Clock) context.Context {
+	if clock == nil {
+		panic("clock can't be nil")
+	}
+
+	return context.WithValue(ctx, clockKey{}, clock)
+}
+
+// ClockFrom returns the clock carried by ctx, or the system clock, which is
+// what system.Clock reads, if there is none.
+func ClockFrom(ctx context.Context) Clock {
+	if clock, ok := ctx.Value(clockKey{}).(Clock); ok {
+		return clock
+	}
+
+	return systemClock{}
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
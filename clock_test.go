@@ -0,0 +1,66 @@
+package timing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClockFrom(t *testing.T) {
+	t.Parallel()
+
+	clock := &pendingEventScheduler{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want Clock
+	}{
+		{
+			name: "no clock",
+			ctx:  context.Background(),
+			want: systemClock{},
+		},
+		{
+			name: "clock",
+			ctx:  WithClock(context.Background(), clock),
+			want: clock,
+		},
+		{
+			name: "derived context",
+			ctx:  WithJobName(WithClock(context.Background(), clock), "job"),
+			want: clock,
+		},
+		{
+			name: "other value under the same string",
+			ctx:  context.WithValue(context.Background(), "actionContextClock", clock),
+			want: systemClock{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, ClockFrom(tt.ctx))
+		})
+	}
+}
+
+func TestClockFrom_systemClock(t *testing.T) {
+	t.Parallel()
+
+	before := time.Now()
+	now := ClockFrom(context.Background()).Now()
+
+	require.False(t, now.Before(before))
+	require.False(t, now.After(time.Now()))
+}
+
+func TestWithClock_nilClock(t *testing.T) {
+	t.Parallel()
+
+	require.PanicsWithValue(t, "clock can't be nil", func() { WithClock(context.Background(), nil) })
+}
@@ -0,0 +1,75 @@
+// Package cluster simulates several nodes of a distributed system on one
+// simulated timeline. Every node is a timing.EventScheduler with its own,
+// possibly distorted, clock, and the nodes talk to each other through a
+// simulated Network whose deliveries are events of the shared scheduler.
+package cluster
+
+import (
+	"sync"
+
+	"github.com/metamogul/timing"
+)
+
+// Cluster ties nodes and their network to a shared scheduler, usually a
+// simulated_time.SerialEventScheduler or AsyncEventScheduler, which is
+// forwarded directly to run the simulation.
+type Cluster struct {
+	scheduler timing.EventScheduler
+	network   *Network
+
+	mu    sync.RWMutex
+	nodes map[string]*Node
+}
+
+// New returns a cluster without nodes. The seed makes the network's
+// latencies, losses and reorderings reproducible.
+func New(scheduler timing.EventScheduler, seed uint64) *Cluster {
+	if scheduler == nil {
+		panic("scheduler can't be nil")
+	}
+
+	c := &Cluster{
+		scheduler: scheduler,
+		nodes:     make(map[string]*Node),
+	}
+	c.network = newNetwork(c, seed)
+
+	return c
+}
+
+// AddNode adds a node that reads time from clock, which should be derived
+// from the cluster's scheduler, e.g. a simulated_time.DriftingClock wrapping
+// it. A nil clock gives the node the scheduler's undistorted time.
+func (c *Cluster) AddNode(name string, clock timing.Clock) *Node {
+	if clock == nil {
+		clock = c.scheduler
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.nodes[name]; exists {
+		panic("node " + name + " already exists")
+	}
+
+	node := newNode(name, c.scheduler, clock, c.network)
+	c.nodes[name] = node
+
+	return node
+}
+
+// Node returns the node called name, or nil if there is none.
+func (c *Cluster) Node(name string) *Node {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.nodes[name]
+}
+
+func (c *Cluster) Network() *Network {
+	return c.network
+}
+
+func (c *Cluster) Scheduler() timing.EventScheduler {
+	return c.scheduler
+}
@@ -0,0 +1,44 @@
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metamogul/timing/simulated_time"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	scheduler := simulated_time.NewSerialEventScheduler(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+
+	c := New(scheduler, 1)
+	require.NotNil(t, c)
+	require.Equal(t, scheduler, c.Scheduler())
+	require.NotNil(t, c.Network())
+
+	require.PanicsWithValue(t, "scheduler can't be nil", func() { New(nil, 1) })
+}
+
+func TestCluster_AddNode(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	scheduler := simulated_time.NewSerialEventScheduler(now)
+
+	c := New(scheduler, 1)
+
+	a := c.AddNode("a", nil)
+	b := c.AddNode("b", simulated_time.NewSkewedClock(scheduler, time.Minute))
+
+	require.Equal(t, "a", a.Name())
+	require.Equal(t, a, c.Node("a"))
+	require.Equal(t, b, c.Node("b"))
+	require.Nil(t, c.Node("c"))
+
+	require.Equal(t, now, a.Now())
+	require.Equal(t, now.Add(time.Minute), b.Now())
+
+	require.PanicsWithValue(t, "node a already exists", func() { c.AddNode("a", nil) })
+}
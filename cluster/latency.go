@@ -0,0 +1,53 @@
+package cluster
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// Latency draws the time a message takes to travel a link.
+type Latency func(rng *rand.Rand) time.Duration
+
+func Constant(latency time.Duration) Latency {
+	if latency < 0 {
+		panic("latency can't be negative")
+	}
+
+	return func(*rand.Rand) time.Duration {
+		return latency
+	}
+}
+
+// Uniform draws latencies evenly from [low, high].
+func Uniform(low, high time.Duration) Latency {
+	if low < 0 || high < low {
+		panic("low must not be negative nor greater than high")
+	}
+
+	return func(rng *rand.Rand) time.Duration {
+		return low + time.Duration(rng.Int64N(int64(high-low)+1))
+	}
+}
+
+// Normal draws normally distributed latencies, cut off at zero.
+func Normal(mean, stddev time.Duration) Latency {
+	if mean < 0 || stddev < 0 {
+		panic("mean and stddev can't be negative")
+	}
+
+	return func(rng *rand.Rand) time.Duration {
+		return max(0, mean+time.Duration(rng.NormFloat64()*float64(stddev)))
+	}
+}
+
+// Exponential draws exponentially distributed latencies, which model a link
+// with mostly fast and a few very slow deliveries.
+func Exponential(mean time.Duration) Latency {
+	if mean < 0 {
+		panic("mean can't be negative")
+	}
+
+	return func(rng *rand.Rand) time.Duration {
+		return time.Duration(rng.ExpFloat64() * float64(mean))
+	}
+}
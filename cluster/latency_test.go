@@ -0,0 +1,61 @@
+package cluster
+
+import (
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatency(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		latency  Latency
+		min, max time.Duration
+	}{
+		{name: "constant", latency: Constant(5 * time.Millisecond), min: 5 * time.Millisecond, max: 5 * time.Millisecond},
+		{name: "uniform", latency: Uniform(time.Millisecond, 3*time.Millisecond), min: time.Millisecond, max: 3 * time.Millisecond},
+		{name: "normal", latency: Normal(time.Millisecond, 10*time.Millisecond), min: 0, max: time.Second},
+		{name: "exponential", latency: Exponential(time.Millisecond), min: 0, max: time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rng := rand.New(rand.NewPCG(1, 1))
+
+			for range 1000 {
+				latency := tt.latency(rng)
+				require.GreaterOrEqual(t, latency, tt.min)
+				require.LessOrEqual(t, latency, tt.max)
+			}
+		})
+	}
+}
+
+func TestLatency_mean(t *testing.T) {
+	t.Parallel()
+
+	rng := rand.New(rand.NewPCG(1, 1))
+
+	for _, latency := range []Latency{Normal(10*time.Millisecond, time.Millisecond), Exponential(10 * time.Millisecond)} {
+		var sum time.Duration
+		for range 10000 {
+			sum += latency(rng)
+		}
+
+		require.InDelta(t, 10*time.Millisecond, sum/10000, float64(time.Millisecond))
+	}
+}
+
+func TestLatency_invalid(t *testing.T) {
+	t.Parallel()
+
+	require.PanicsWithValue(t, "latency can't be negative", func() { Constant(-1) })
+	require.PanicsWithValue(t, "low must not be negative nor greater than high", func() { Uniform(2, 1) })
+	require.PanicsWithValue(t, "mean and stddev can't be negative", func() { Normal(1, -1) })
+	require.PanicsWithValue(t, "mean can't be negative", func() { Exponential(-1) })
+}
@@ -0,0 +1,217 @@
+package cluster
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/metamogul/timing"
+)
+
+// Message is what a Handler receives. SentAt is read on the sender's clock.
+type Message struct {
+	From    string
+	To      string
+	Payload any
+	SentAt  time.Time
+}
+
+// Handler receives messages on the receiving node's clock.
+type Handler func(ctx timing.ActionContext, message Message)
+
+// Link describes how messages travel from one node to another.
+type Link struct {
+	// Latency draws the delivery delay of each message. Nil delivers
+	// immediately.
+	Latency Latency
+	// Loss is the probability that a message is dropped.
+	Loss float64
+	// Reordering is the probability that a message may overtake the messages
+	// sent before it. Otherwise messages on a link arrive in the order they
+	// were sent, even if their latencies would reorder them.
+	Reordering float64
+}
+
+func (l Link) validate() {
+	if l.Loss < 0 || l.Loss > 1 || l.Reordering < 0 || l.Reordering > 1 {
+		panic("loss and reordering must be between 0 and 1")
+	}
+}
+
+// NetworkStats counts the messages a Network has handled so far.
+type NetworkStats struct {
+	Sent      int
+	Delivered int
+	Dropped   int
+}
+
+type route struct {
+	from, to string
+}
+
+// Network delivers messages between the nodes of a Cluster. Each delivery is
+// an event of the cluster's scheduler, performed on the receiving node.
+type Network struct {
+	cluster *Cluster
+
+	mu           sync.Mutex
+	rng          *rand.Rand
+	defaultLink  Link
+	links        map[route]Link
+	lastArrivals map[route]time.Time
+	partitions   map[string]int
+	handlers     map[string]Handler
+	stats        NetworkStats
+}
+
+func newNetwork(cluster *Cluster, seed uint64) *Network {
+	return &Network{
+		cluster:      cluster,
+		rng:          rand.New(rand.NewPCG(seed, seed)),
+		links:        make(map[route]Link),
+		lastArrivals: make(map[route]time.Time),
+		handlers:     make(map[string]Handler),
+	}
+}
+
+// SetDefaultLink configures all links without a link of their own.
+func (n *Network) SetDefaultLink(link Link) {
+	link.validate()
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.defaultLink = link
+}
+
+// SetLink configures the link from one node to another. The way back is
+// configured separately.
+func (n *Network) SetLink(from, to string, link Link) {
+	link.validate()
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.links[route{from, to}] = link
+}
+
+// Partition splits the given nodes into groups that can't reach each other,
+// replacing any previous partition. Nodes in no group reach everyone.
+// Messages already in flight across the partition are dropped as well.
+func (n *Network) Partition(groups ...[]string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.partitions = make(map[string]int)
+	for i, group := range groups {
+		for _, node := range group {
+			n.partitions[node] = i
+		}
+	}
+}
+
+// Heal lifts the partition.
+func (n *Network) Heal() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.partitions = nil
+}
+
+func (n *Network) Stats() NetworkStats {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.stats
+}
+
+func (n *Network) listen(node string, handler Handler) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.handlers[node] = handler
+}
+
+func (n *Network) send(from *Node, to string, payload any, ctx context.Context) {
+	receiver := n.cluster.Node(to)
+	if receiver == nil {
+		panic("node " + to + " doesn't exist")
+	}
+
+	message := Message{
+		From:    from.name,
+		To:      to,
+		Payload: payload,
+		SentAt:  from.Now(),
+	}
+
+	delay, ok := n.route(message)
+	if !ok {
+		return
+	}
+
+	receiver.PerformAfter(timing.ActionFunc(func(actionContext timing.ActionContext) {
+		n.deliver(actionContext, message)
+	}), delay, ctx)
+}
+
+// route draws whether and after how long message arrives.
+func (n *Network) route(message Message) (delay time.Duration, ok bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.stats.Sent++
+
+	r := route{message.From, message.To}
+	link, exists := n.links[r]
+	if !exists {
+		link = n.defaultLink
+	}
+
+	if n.partitioned(r) || n.rng.Float64() < link.Loss {
+		n.stats.Dropped++
+		return 0, false
+	}
+
+	if link.Latency != nil {
+		delay = link.Latency(n.rng)
+	}
+
+	now := n.cluster.scheduler.Now()
+	arrival := now.Add(delay)
+
+	if lastArrival := n.lastArrivals[r]; arrival.Before(lastArrival) {
+		if n.rng.Float64() >= link.Reordering {
+			arrival = lastArrival
+			delay = arrival.Sub(now)
+		}
+	}
+
+	if arrival.After(n.lastArrivals[r]) {
+		n.lastArrivals[r] = arrival
+	}
+
+	return delay, true
+}
+
+func (n *Network) deliver(ctx timing.ActionContext, message Message) {
+	n.mu.Lock()
+	handler := n.handlers[message.To]
+	if handler == nil || n.partitioned(route{message.From, message.To}) {
+		n.stats.Dropped++
+		n.mu.Unlock()
+		return
+	}
+	n.stats.Delivered++
+	n.mu.Unlock()
+
+	handler(ctx, message)
+}
+
+func (n *Network) partitioned(r route) bool {
+	fromGroup, fromPartitioned := n.partitions[r.from]
+	toGroup, toPartitioned := n.partitions[r.to]
+
+	return fromPartitioned && toPartitioned && fromGroup != toGroup
+}
@@ -0,0 +1,155 @@
+package cluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/metamogul/timing"
+	"github.com/metamogul/timing/simulated_time"
+	"github.com/stretchr/testify/require"
+)
+
+type received struct {
+	message Message
+	at      time.Time
+}
+
+func newTestCluster(now time.Time) (*simulated_time.SerialEventScheduler, *Cluster, *Node, *Node, *[]received) {
+	scheduler := simulated_time.NewSerialEventScheduler(now)
+
+	c := New(scheduler, 1)
+	a := c.AddNode("a", nil)
+	b := c.AddNode("b", simulated_time.NewSkewedClock(scheduler, time.Second))
+
+	receivedByB := make([]received, 0)
+	b.Listen(func(ctx timing.ActionContext, message Message) {
+		receivedByB = append(receivedByB, received{message, ctx.Clock().Now()})
+	})
+
+	return scheduler, c, a, b, &receivedByB
+}
+
+func TestNetwork_send(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	scheduler, c, a, _, receivedByB := newTestCluster(now)
+
+	c.Network().SetLink("a", "b", Link{Latency: Constant(10 * time.Millisecond)})
+
+	a.Send("b", "hello", context.Background())
+
+	scheduler.Forward(9 * time.Millisecond)
+	require.Empty(t, *receivedByB)
+
+	scheduler.Forward(time.Millisecond)
+	require.Equal(t, []received{{
+		message: Message{From: "a", To: "b", Payload: "hello", SentAt: now},
+		at:      now.Add(10*time.Millisecond + time.Second),
+	}}, *receivedByB)
+	require.Equal(t, NetworkStats{Sent: 1, Delivered: 1}, c.Network().Stats())
+
+	require.PanicsWithValue(t, "node c doesn't exist", func() { a.Send("c", nil, context.Background()) })
+}
+
+func TestNetwork_noHandler(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	scheduler, c, _, b, _ := newTestCluster(now)
+
+	b.Send("a", "unheard", context.Background())
+	scheduler.Forward(time.Second)
+
+	require.Equal(t, NetworkStats{Sent: 1, Dropped: 1}, c.Network().Stats())
+}
+
+func TestNetwork_loss(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	scheduler, c, a, _, receivedByB := newTestCluster(now)
+
+	c.Network().SetDefaultLink(Link{Latency: Constant(time.Millisecond), Loss: 0.5})
+
+	for i := range 1000 {
+		a.Send("b", i, context.Background())
+	}
+	scheduler.Forward(time.Second)
+
+	stats := c.Network().Stats()
+	require.Equal(t, 1000, stats.Sent)
+	require.Equal(t, len(*receivedByB), stats.Delivered)
+	require.Equal(t, 1000, stats.Delivered+stats.Dropped)
+	require.InDelta(t, 500, stats.Dropped, 100)
+
+	require.PanicsWithValue(t, "loss and reordering must be between 0 and 1", func() {
+		c.Network().SetDefaultLink(Link{Loss: 2})
+	})
+}
+
+func TestNetwork_reordering(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		reordering float64
+		inOrder    bool
+	}{
+		{name: "in order", reordering: 0, inOrder: true},
+		{name: "reordered", reordering: 1, inOrder: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			scheduler, c, a, _, receivedByB := newTestCluster(now)
+
+			c.Network().SetDefaultLink(Link{Latency: Uniform(0, 100*time.Millisecond), Reordering: tt.reordering})
+
+			for i := range 100 {
+				a.Send("b", i, context.Background())
+				scheduler.Forward(time.Millisecond)
+			}
+			scheduler.Forward(time.Second)
+
+			require.Len(t, *receivedByB, 100)
+
+			inOrder := true
+			for i, r := range *receivedByB {
+				if r.message.Payload != i {
+					inOrder = false
+				}
+			}
+			require.Equal(t, tt.inOrder, inOrder)
+		})
+	}
+}
+
+func TestNetwork_Partition(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	scheduler, c, a, _, receivedByB := newTestCluster(now)
+	c.AddNode("c", nil)
+
+	c.Network().SetDefaultLink(Link{Latency: Constant(10 * time.Millisecond)})
+
+	a.Send("b", "in flight", context.Background())
+	c.Network().Partition([]string{"a", "c"}, []string{"b"})
+	a.Send("b", "partitioned", context.Background())
+	scheduler.Forward(time.Second)
+
+	require.Empty(t, *receivedByB)
+	require.Equal(t, NetworkStats{Sent: 2, Dropped: 2}, c.Network().Stats())
+
+	c.Network().Heal()
+	a.Send("b", "healed", context.Background())
+	scheduler.Forward(time.Second)
+
+	require.Len(t, *receivedByB, 1)
+	require.Equal(t, "healed", (*receivedByB)[0].message.Payload)
+}
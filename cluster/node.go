@@ -0,0 +1,117 @@
+package cluster
+
+import (
+	"context"
+	"time"
+
+	"github.com/metamogul/timing"
+)
+
+// Node is a single member of a Cluster. It schedules on the cluster's shared
+// scheduler, but its actions see the node's own clock, both through Now and
+// through the ActionContext they are performed with.
+//
+// Intervals are measured in the shared scheduler's time, while the until of
+// PerformRepeatedly is read on the node's clock.
+type Node struct {
+	name      string
+	scheduler timing.EventScheduler
+	clock     timing.Clock
+	network   *Network
+}
+
+func newNode(name string, scheduler timing.EventScheduler, clock timing.Clock, network *Network) *Node {
+	return &Node{
+		name:      name,
+		scheduler: scheduler,
+		clock:     clock,
+		network:   network,
+	}
+}
+
+func (n *Node) Name() string {
+	return n.name
+}
+
+func (n *Node) Now() time.Time {
+	return n.clock.Now()
+}
+
+func (n *Node) PerformNow(action timing.Action, ctx context.Context) {
+	n.scheduler.PerformNow(action, n.withClock(ctx))
+}
+
+func (n *Node) PerformAfter(action timing.Action, duration time.Duration, ctx context.Context) {
+	n.scheduler.PerformAfter(action, duration, n.withClock(ctx))
+}
+
+func (n *Node) PerformRepeatedly(action timing.Action, until *time.Time, interval time.Duration, ctx context.Context) {
+	if until != nil {
+		sharedUntil := until.Add(n.scheduler.Now().Sub(n.clock.Now()))
+		until = &sharedUntil
+	}
+
+	n.scheduler.PerformRepeatedly(action, until, interval, n.withClock(ctx))
+}
+
+// Listen makes handler receive the messages sent to this node, replacing any
+// previous handler. Messages sent while the node has no handler are dropped.
+func (n *Node) Listen(handler Handler) {
+	n.network.listen(n.name, handler)
+}
+
+// Send sends payload to the node called to through the cluster's network.
+func (n *Node) Send(to string, payload any, ctx context.Context) {
+	n.network.send(n, to, payload, ctx)
+}
+
+type nodeKey struct{}
+
+// withClock makes actions scheduled with the returned context see the node's
+// clock. The node is kept under a single context key, so that a message
+// passed on from node to node replaces it on every hop, while the middleware
+// reading it is installed only once. Being a middleware rather than a wrapper
+// around the action keeps simulated_time.SchedulingAction intact.
+func (n *Node) withClock(ctx context.Context) context.Context {
+	_, installed := ctx.Value(nodeKey{}).(*Node)
+	ctx = context.WithValue(ctx, nodeKey{}, n)
+
+	if installed {
+		return ctx
+	}
+
+	return timing.WithMiddleware(ctx, nodeMiddleware)
+}
+
+func nodeMiddleware(action timing.Action) timing.Action {
+	return timing.ActionFunc(func(actionContext timing.ActionContext) {
+		n := actionContext.Value(nodeKey{}).(*Node)
+
+		action.Perform(&nodeActionContext{
+			ActionContext: timing.DeriveActionContext(actionContext, timing.WithClock(actionContext, n.clock)),
+			node:          n,
+		})
+	})
+}
+
+type nodeActionContext struct {
+	timing.ActionContext
+	node *Node
+}
+
+func (n *nodeActionContext) Clock() timing.Clock {
+	return n.node.clock
+}
+
+// Scheduler returns the node, so that follow-up work is scheduled on it, too.
+func (n *nodeActionContext) Scheduler() timing.EventScheduler {
+	return n.node
+}
+
+func (n *nodeActionContext) Value(key any) any {
+	if key == timing.ActionContextClockKey {
+		return n.node.clock
+	}
+
+	return n.ActionContext.Value(key)
+}
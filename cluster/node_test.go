@@ -0,0 +1,142 @@
+package cluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/metamogul/timing"
+	"github.com/metamogul/timing/simulated_time"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNode_actionsSeeNodeClock(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	scheduler := simulated_time.NewSerialEventScheduler(now)
+
+	c := New(scheduler, 1)
+	a := c.AddNode("a", nil)
+	b := c.AddNode("b", simulated_time.NewSkewedClock(scheduler, time.Second))
+
+	var seenByA, seenByB, valueSeenByB, clockFromSeenByB time.Time
+	a.PerformAfter(timing.ActionFunc(func(ctx timing.ActionContext) {
+		seenByA = ctx.Clock().Now()
+	}), time.Minute, context.Background())
+	b.PerformAfter(timing.ActionFunc(func(ctx timing.ActionContext) {
+		seenByB = ctx.Clock().Now()
+		valueSeenByB = ctx.Value(timing.ActionContextClockKey).(timing.Clock).Now()
+		clockFromSeenByB = timing.ClockFrom(ctx).Now()
+	}), time.Minute, context.Background())
+
+	scheduler.Forward(time.Minute)
+
+	require.Equal(t, now.Add(time.Minute), seenByA)
+	require.Equal(t, now.Add(time.Minute+time.Second), seenByB)
+	require.Equal(t, seenByB, valueSeenByB)
+	require.Equal(t, seenByB, clockFromSeenByB)
+}
+
+func TestNode_actionsScheduleOnNode(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	scheduler := simulated_time.NewSerialEventScheduler(now)
+
+	c := New(scheduler, 1)
+	b := c.AddNode("b", simulated_time.NewSkewedClock(scheduler, time.Second))
+
+	var followUpSeen time.Time
+	b.PerformAfter(timing.ActionFunc(func(ctx timing.ActionContext) {
+		require.Same(t, b, ctx.Scheduler())
+
+		ctx.Scheduler().PerformAfter(timing.ActionFunc(func(ctx timing.ActionContext) {
+			followUpSeen = ctx.Clock().Now()
+		}), time.Minute, context.Background())
+	}), time.Minute, context.Background())
+
+	scheduler.Forward(2 * time.Minute)
+
+	require.Equal(t, now.Add(2*time.Minute+time.Second), followUpSeen)
+}
+
+func TestNode_PerformRepeatedly(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	scheduler := simulated_time.NewSerialEventScheduler(now)
+
+	c := New(scheduler, 1)
+	behind := c.AddNode("behind", simulated_time.NewSkewedClock(scheduler, -time.Hour))
+
+	performed := 0
+	behind.PerformRepeatedly(timing.ActionFunc(func(timing.ActionContext) {
+		performed++
+	}), ptr(now.Add(-time.Hour+4*time.Minute)), time.Minute, context.Background())
+
+	scheduler.Forward(time.Hour)
+
+	require.Equal(t, 3, performed)
+}
+
+func TestNode_PerformNow_schedulingAction(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	scheduler := simulated_time.NewAsyncEventScheduler(now)
+
+	c := New(scheduler, 1)
+	a := c.AddNode("a", simulated_time.NewSkewedClock(scheduler, time.Second))
+
+	performedAt := make(chan time.Time, 1)
+	a.PerformNow(simulated_time.NewSchedulingAction(timing.ActionFunc(func(ctx timing.ActionContext) {
+		a.PerformAfter(timing.ActionFunc(func(ctx timing.ActionContext) {
+			performedAt <- ctx.Clock().Now()
+		}), time.Minute, context.Background())
+		ctx.DoneSchedulingNewEvents()
+	})), context.Background())
+
+	scheduler.ForwardToNextEvent()
+	scheduler.Forward(time.Minute)
+
+	require.Equal(t, now.Add(time.Minute+time.Second), <-performedAt)
+}
+
+// TestNode_messageChain passes a message back and forth, so that every hop
+// is scheduled with the context of the previous one. The middlewares in the
+// context must not pile up along the way, or each hop gets more expensive.
+func TestNode_messageChain(t *testing.T) {
+	t.Parallel()
+
+	const hops = 1000
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	scheduler := simulated_time.NewSerialEventScheduler(now)
+
+	c := New(scheduler, 1)
+	a := c.AddNode("a", simulated_time.NewSkewedClock(scheduler, -time.Second))
+	b := c.AddNode("b", simulated_time.NewSkewedClock(scheduler, time.Second))
+
+	received := 0
+	pass := func(self *Node, other string) Handler {
+		return func(ctx timing.ActionContext, message Message) {
+			received++
+
+			require.Equal(t, self.Now(), ctx.Clock().Now())
+			require.Same(t, self, ctx.Scheduler())
+			require.Len(t, timing.MiddlewareFrom(ctx), 1)
+
+			if hop := message.Payload.(int); hop < hops {
+				self.Send(other, hop+1, ctx)
+			}
+		}
+	}
+	a.Listen(pass(a, "b"))
+	b.Listen(pass(b, "a"))
+
+	a.Send("b", 1, context.Background())
+	scheduler.Forward(time.Second)
+
+	require.Equal(t, hops, received)
+}
@@ -0,0 +1,5 @@
+package cluster
+
+func ptr[T any](t T) *T {
+	return &t
+}
@@ -0,0 +1,111 @@
+package timing
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// deadlineContext closes its own done channel rather than exposing the one of
+// the context it wraps, so that contexts derived from it take their error
+// from Err instead of inheriting context.Canceled.
+type deadlineContext struct {
+	context.Context
+	deadline time.Time
+	done     chan struct{}
+	closed   sync.Once
+}
+
+func (d *deadlineContext) Deadline() (time.Time, bool) {
+	if parentDeadline, ok := d.Context.Deadline(); ok && parentDeadline.Before(d.deadline) {
+		return parentDeadline, true
+	}
+
+	return d.deadline, true
+}
+
+func (d *deadlineContext) Done() <-chan struct{} {
+	return d.done
+}
+
+func (d *deadlineContext) Err() error {
+	select {
+	case <-d.done:
+	default:
+		return nil
+	}
+
+	if errors.Is(context.Cause(d.Context), context.DeadlineExceeded) {
+		return context.DeadlineExceeded
+	}
+
+	return d.Context.Err()
+}
+
+func (d *deadlineContext) close() {
+	d.closed.Do(func() { close(d.done) })
+}
+
+// WithDeadline is like context.WithDeadline, except that the deadline is
+// measured on the clock of scheduler, which cancels the returned context
+// through PerformAfter. With a simulated scheduler the context thus expires
+// while time is forwarded, with context.DeadlineExceeded as its error and
+// cause, which contexts derived from it report as well.
+//
+// A deadline of parent is not compared to deadline, since it may be measured
+// on another clock, e.g. in real time. The returned context is cancelled with
+// parent, and Deadline reports whichever of the two deadlines is earlier.
+func WithDeadline(scheduler EventScheduler, parent context.Context, deadline time.Time) (context.Context, context.CancelFunc) {
+	if scheduler == nil {
+		panic("scheduler can't be nil")
+	}
+
+	ctx, cancelCause := context.WithCancelCause(parent)
+	deadlineCtx := &deadlineContext{Context: ctx, deadline: deadline, done: make(chan struct{})}
+
+	timeout := deadline.Sub(scheduler.Now())
+	if timeout <= 0 {
+		cancelCause(context.DeadlineExceeded)
+		deadlineCtx.close()
+		return deadlineCtx, func() {}
+	}
+
+	context.AfterFunc(ctx, deadlineCtx.close)
+
+	// The timer is scheduled without the values of parent, so that the
+	// middlewares and job name of parent don't apply to it. The scheduler's
+	// own Middleware, Metrics and Hooks still see it, as an event without a
+	// job name.
+	timerCtx, stopTimer := context.WithCancel(context.Background())
+	context.AfterFunc(ctx, stopTimer)
+
+	cancel := func() {
+		cancelCause(context.Canceled)
+		deadlineCtx.close()
+		stopTimer()
+	}
+
+	scheduler.PerformAfter(ActionFunc(func(ActionContext) {
+		cancelCause(context.DeadlineExceeded)
+		deadlineCtx.close()
+	}), timeout, timerCtx)
+
+	return deadlineCtx, cancel
+}
+
+// WithTimeout is like context.WithTimeout, with the timeout measured on the
+// clock of scheduler as described for WithDeadline.
+func WithTimeout(scheduler EventScheduler, parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return WithDeadline(scheduler, parent, scheduler.Now().Add(timeout))
+}
+
+// DeadlineScheduler is implemented by schedulers that derive contexts with
+// deadlines on their own clock: the simulated schedulers, and
+// system.EventScheduler, which falls back to the real-time functions of the
+// context package.
+type DeadlineScheduler interface {
+	EventScheduler
+	WithDeadline(ctx context.Context, deadline time.Time) (context.Context, context.CancelFunc)
+	WithTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc)
+}
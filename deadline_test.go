@@ -0,0 +1,174 @@
+package timing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithDeadline(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("no scheduler", func(t *testing.T) {
+		t.Parallel()
+
+		require.Panics(t, func() {
+			_, _ = WithDeadline(nil, context.Background(), now)
+		})
+	})
+
+	t.Run("deadline exceeded", func(t *testing.T) {
+		t.Parallel()
+
+		scheduler := &pendingEventScheduler{now: now}
+
+		ctx, cancel := WithDeadline(scheduler, context.WithValue(context.Background(), jobNameKey{}, "job"), now.Add(time.Minute))
+		defer cancel()
+
+		deadline, ok := ctx.Deadline()
+		require.True(t, ok)
+		require.Equal(t, now.Add(time.Minute), deadline)
+		require.NoError(t, ctx.Err())
+
+		require.Len(t, scheduler.pending, 1)
+		require.Equal(t, time.Minute, scheduler.pending[0].duration)
+		require.Empty(t, JobNameFrom(scheduler.pending[0].ctx))
+
+		scheduler.fire()
+
+		<-ctx.Done()
+		require.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+		require.ErrorIs(t, context.Cause(ctx), context.DeadlineExceeded)
+		require.Equal(t, "job", JobNameFrom(ctx))
+
+		child, cancelChild := context.WithCancel(ctx)
+		defer cancelChild()
+		require.ErrorIs(t, child.Err(), context.DeadlineExceeded)
+		require.ErrorIs(t, context.Cause(child), context.DeadlineExceeded)
+	})
+
+	t.Run("deadline exceeded in derived context", func(t *testing.T) {
+		t.Parallel()
+
+		scheduler := &pendingEventScheduler{now: now}
+
+		ctx, cancel := WithDeadline(scheduler, context.Background(), now.Add(time.Minute))
+		defer cancel()
+
+		child, cancelChild := context.WithCancel(ctx)
+		defer cancelChild()
+
+		scheduler.fire()
+
+		<-child.Done()
+		require.ErrorIs(t, child.Err(), context.DeadlineExceeded)
+		require.ErrorIs(t, context.Cause(child), context.DeadlineExceeded)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		scheduler := &pendingEventScheduler{now: now}
+
+		ctx, cancel := WithDeadline(scheduler, context.Background(), now.Add(time.Minute))
+		cancel()
+
+		require.ErrorIs(t, ctx.Err(), context.Canceled)
+		require.Error(t, scheduler.pending[0].ctx.Err())
+	})
+
+	t.Run("parent cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		scheduler := &pendingEventScheduler{now: now}
+
+		parent, cancelParent := context.WithCancel(context.Background())
+		ctx, cancel := WithDeadline(scheduler, parent, now.Add(time.Minute))
+		defer cancel()
+
+		cancelParent()
+		<-ctx.Done()
+
+		require.ErrorIs(t, ctx.Err(), context.Canceled)
+		require.Eventually(t, func() bool { return scheduler.pending[0].ctx.Err() != nil }, time.Second, time.Millisecond)
+	})
+
+	t.Run("deadline already passed", func(t *testing.T) {
+		t.Parallel()
+
+		scheduler := &pendingEventScheduler{now: now}
+
+		ctx, cancel := WithDeadline(scheduler, context.Background(), now)
+		defer cancel()
+
+		require.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+		require.ErrorIs(t, context.Cause(ctx), context.DeadlineExceeded)
+		require.Empty(t, scheduler.pending)
+
+		child, cancelChild := context.WithCancel(ctx)
+		defer cancelChild()
+		require.ErrorIs(t, child.Err(), context.DeadlineExceeded)
+	})
+
+	t.Run("parent deadline earlier", func(t *testing.T) {
+		t.Parallel()
+
+		scheduler := &pendingEventScheduler{now: now}
+
+		parent, cancelParent := WithDeadline(scheduler, context.Background(), now.Add(time.Second))
+		defer cancelParent()
+
+		ctx, cancel := WithDeadline(scheduler, parent, now.Add(time.Minute))
+		defer cancel()
+
+		deadline, ok := ctx.Deadline()
+		require.True(t, ok)
+		require.Equal(t, now.Add(time.Second), deadline)
+		require.Len(t, scheduler.pending, 2)
+
+		scheduler.pending[0].action.Perform(nil)
+
+		<-ctx.Done()
+		require.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+		require.ErrorIs(t, context.Cause(ctx), context.DeadlineExceeded)
+	})
+
+	t.Run("real-time parent deadline earlier", func(t *testing.T) {
+		t.Parallel()
+
+		scheduler := &pendingEventScheduler{now: now.AddDate(100, 0, 0)}
+
+		parent, cancelParent := context.WithTimeout(context.Background(), time.Hour)
+		defer cancelParent()
+
+		ctx, cancel := WithDeadline(scheduler, parent, scheduler.now.Add(time.Minute))
+		defer cancel()
+
+		require.Len(t, scheduler.pending, 1)
+		require.NoError(t, ctx.Err())
+
+		scheduler.fire()
+
+		<-ctx.Done()
+		require.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+	})
+}
+
+func TestWithTimeout(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	scheduler := &pendingEventScheduler{now: now}
+
+	ctx, cancel := WithTimeout(scheduler, context.Background(), time.Hour)
+	defer cancel()
+
+	deadline, ok := ctx.Deadline()
+	require.True(t, ok)
+	require.Equal(t, now.Add(time.Hour), deadline)
+	require.Equal(t, time.Hour, scheduler.pending[0].duration)
+}
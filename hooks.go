@@ -0,0 +1,30 @@
+package timing
+
+// Hooks are called by event schedulers along the lifecycle of the events
+// they schedule, with the metadata of the event concerned. Any of them may
+// be nil.
+//
+// Hooks are called synchronously, possibly while the scheduler holds its
+// locks. They must return quickly and must not schedule events on the
+// scheduler calling them.
+type Hooks struct {
+	// OnScheduled is called once per call to a Perform method, even for
+	// repeated actions, with the first occurrence.
+	OnScheduled func(EventMetadata)
+	// OnBeforePerform and OnAfterPerform bracket every single performance of
+	// an action, including its middleware. OnAfterPerform is called even if
+	// the action panics.
+	OnBeforePerform func(EventMetadata)
+	OnAfterPerform  func(EventMetadata)
+	// OnCancelled is called when the context of a scheduled action is
+	// cancelled while it still has occurrences left, with the first
+	// occurrence that won't be performed.
+	OnCancelled func(EventMetadata)
+	// OnGeneratorFinished is called when an action or generator has no
+	// occurrences left without being cancelled, with the last occurrence.
+	OnGeneratorFinished func(EventMetadata)
+	// OnClockAdvanced is called when a simulated scheduler moves its clock
+	// forward, with the new time as ScheduledTime and the job of the event
+	// the clock moved to, if any. Schedulers in real time never call it.
+	OnClockAdvanced func(EventMetadata)
+}
@@ -0,0 +1,37 @@
+package instrument
+
+import (
+	"github.com/metamogul/timing"
+)
+
+// Scheduled calls hooks.OnScheduled, if hooks and the hook are set.
+// Cancelled, GeneratorFinished and ClockAdvanced do the same for theirs.
+func Scheduled(hooks *timing.Hooks, metadata timing.EventMetadata) {
+	if hooks != nil {
+		call(hooks.OnScheduled, metadata)
+	}
+}
+
+func Cancelled(hooks *timing.Hooks, metadata timing.EventMetadata) {
+	if hooks != nil {
+		call(hooks.OnCancelled, metadata)
+	}
+}
+
+func GeneratorFinished(hooks *timing.Hooks, metadata timing.EventMetadata) {
+	if hooks != nil {
+		call(hooks.OnGeneratorFinished, metadata)
+	}
+}
+
+func ClockAdvanced(hooks *timing.Hooks, metadata timing.EventMetadata) {
+	if hooks != nil {
+		call(hooks.OnClockAdvanced, metadata)
+	}
+}
+
+func call(hook func(timing.EventMetadata), metadata timing.EventMetadata) {
+	if hook != nil {
+		hook(metadata)
+	}
+}
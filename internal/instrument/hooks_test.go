@@ -0,0 +1,38 @@
+package instrument
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metamogul/timing"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHooks(t *testing.T) {
+	t.Parallel()
+
+	metadata := timing.EventMetadata{ScheduledTime: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), JobName: "job"}
+
+	calls := make([]string, 0)
+	record := func(hook string) func(timing.EventMetadata) {
+		return func(called timing.EventMetadata) {
+			require.Equal(t, metadata, called)
+			calls = append(calls, hook)
+		}
+	}
+
+	hooks := &timing.Hooks{
+		OnScheduled:         record("scheduled"),
+		OnCancelled:         record("cancelled"),
+		OnGeneratorFinished: record("finished"),
+		OnClockAdvanced:     record("advanced"),
+	}
+
+	for _, call := range []func(*timing.Hooks, timing.EventMetadata){Scheduled, Cancelled, GeneratorFinished, ClockAdvanced} {
+		call(hooks, metadata)
+		call(&timing.Hooks{}, metadata)
+		call(nil, metadata)
+	}
+
+	require.Equal(t, []string{"scheduled", "cancelled", "finished", "advanced"}, calls)
+}
@@ -0,0 +1,32 @@
+package instrument
+
+import (
+	"github.com/metamogul/timing"
+)
+
+// Perform calls perform for the event described by metadata and reports its
+// lateness and duration, both measured on clock, to metrics, and calls the
+// perform hooks of hooks around it. Without metrics and hooks it only calls
+// perform.
+func Perform(metrics timing.Metrics, hooks *timing.Hooks, clock timing.Clock, metadata timing.EventMetadata, perform func()) {
+	if metrics == nil && hooks == nil {
+		perform()
+		return
+	}
+
+	if hooks != nil {
+		call(hooks.OnBeforePerform, metadata)
+		defer call(hooks.OnAfterPerform, metadata)
+	}
+
+	if metrics != nil {
+		job := metadata.JobName
+
+		startedAt := clock.Now()
+		metrics.EventStarted(job, startedAt.Sub(metadata.ScheduledTime))
+
+		defer func() { metrics.EventFinished(job, clock.Now().Sub(startedAt)) }()
+	}
+
+	perform()
+}
@@ -0,0 +1,91 @@
+package instrument
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metamogul/timing"
+	"github.com/stretchr/testify/require"
+)
+
+type steppingClock struct {
+	now  time.Time
+	step time.Duration
+}
+
+func (s *steppingClock) Now() time.Time {
+	defer func() { s.now = s.now.Add(s.step) }()
+	return s.now
+}
+
+type recordedMetrics struct {
+	job      string
+	lateness time.Duration
+	duration time.Duration
+}
+
+func (r *recordedMetrics) EventScheduled(string) {}
+
+func (r *recordedMetrics) EventCancelled(string) {}
+
+func (r *recordedMetrics) EventStarted(job string, lateness time.Duration) {
+	r.job, r.lateness = job, lateness
+}
+
+func (r *recordedMetrics) EventFinished(_ string, duration time.Duration) {
+	r.duration = duration
+}
+
+func TestPerform(t *testing.T) {
+	t.Parallel()
+
+	scheduledTime := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	metadata := timing.EventMetadata{ScheduledTime: scheduledTime, JobName: "job", EventID: 1, Occurrence: 1}
+
+	t.Run("no metrics", func(t *testing.T) {
+		t.Parallel()
+
+		performed := false
+		Perform(nil, nil, &steppingClock{}, timing.EventMetadata{ScheduledTime: scheduledTime}, func() { performed = true })
+		require.True(t, performed)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		metrics := &recordedMetrics{}
+		clock := &steppingClock{now: scheduledTime.Add(time.Second), step: time.Minute}
+
+		performed := false
+		Perform(metrics, nil, clock, metadata, func() { performed = true })
+
+		require.True(t, performed)
+		require.Equal(t, "job", metrics.job)
+		require.Equal(t, time.Second, metrics.lateness)
+		require.Equal(t, time.Minute, metrics.duration)
+	})
+
+	t.Run("hooks", func(t *testing.T) {
+		t.Parallel()
+
+		calls := make([]string, 0)
+		record := func(hook string) func(timing.EventMetadata) {
+			return func(called timing.EventMetadata) {
+				require.Equal(t, metadata, called)
+				calls = append(calls, hook)
+			}
+		}
+
+		hooks := &timing.Hooks{OnBeforePerform: record("before"), OnAfterPerform: record("after")}
+
+		require.Panics(t, func() {
+			Perform(nil, hooks, &steppingClock{}, metadata, func() {
+				calls = append(calls, "perform")
+				panic("failed")
+			})
+		})
+		require.Equal(t, []string{"before", "perform", "after"}, calls)
+
+		Perform(nil, &timing.Hooks{}, &steppingClock{}, metadata, func() {})
+	})
+}
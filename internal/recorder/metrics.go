@@ -0,0 +1,68 @@
+// Package recorder provides a timing.Metrics that records what it is told,
+// for the tests of the schedulers.
+package recorder
+
+import (
+	"sync"
+	"time"
+)
+
+// Metrics counts reported metrics per job. While a scheduler may still
+// report to it, read the counts with Count and lock it for the other fields.
+type Metrics struct {
+	sync.Mutex
+
+	Scheduled map[string]int
+	Cancelled map[string]int
+	Started   map[string]int
+	Finished  map[string]int
+
+	Lateness  []time.Duration
+	Durations []time.Duration
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{
+		Scheduled: make(map[string]int),
+		Cancelled: make(map[string]int),
+		Started:   make(map[string]int),
+		Finished:  make(map[string]int),
+	}
+}
+
+func (m *Metrics) EventScheduled(job string) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.Scheduled[job]++
+}
+
+func (m *Metrics) EventCancelled(job string) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.Cancelled[job]++
+}
+
+func (m *Metrics) EventStarted(job string, lateness time.Duration) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.Started[job]++
+	m.Lateness = append(m.Lateness, lateness)
+}
+
+func (m *Metrics) EventFinished(job string, duration time.Duration) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.Finished[job]++
+	m.Durations = append(m.Durations, duration)
+}
+
+func (m *Metrics) Count(counts map[string]int, job string) int {
+	m.Lock()
+	defer m.Unlock()
+
+	return counts[job]
+}
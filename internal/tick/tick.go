@@ -0,0 +1,27 @@
+// Package tick rounds times to ticks of a fixed length counted from the Unix
+// epoch, as the timing wheels of system and simulated_time do.
+package tick
+
+import "time"
+
+// Of returns the index of the tick containing t.
+func Of(t time.Time, length time.Duration) int64 {
+	nanos := t.UnixNano()
+
+	index := nanos / int64(length)
+	if nanos%int64(length) < 0 {
+		index--
+	}
+
+	return index
+}
+
+// RoundUp returns the start of the first tick at or after t, in t's location.
+func RoundUp(t time.Time, length time.Duration) time.Time {
+	rounded := time.Unix(0, Of(t, length)*int64(length)).In(t.Location())
+	if rounded.Before(t) {
+		rounded = rounded.Add(length)
+	}
+
+	return rounded
+}
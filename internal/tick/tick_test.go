@@ -0,0 +1,70 @@
+package tick
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundUp(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		t      time.Time
+		length time.Duration
+		want   time.Time
+	}{
+		{
+			name:   "on tick",
+			t:      base,
+			length: 15 * time.Millisecond,
+			want:   base,
+		},
+		{
+			name:   "between ticks",
+			t:      base.Add(time.Millisecond),
+			length: 15 * time.Millisecond,
+			want:   base.Add(15 * time.Millisecond),
+		},
+		{
+			name:   "just before tick",
+			t:      base.Add(15*time.Millisecond - time.Nanosecond),
+			length: 15 * time.Millisecond,
+			want:   base.Add(15 * time.Millisecond),
+		},
+		{
+			name:   "before unix epoch",
+			t:      time.Unix(0, -5).UTC(),
+			length: 10 * time.Nanosecond,
+			want:   time.Unix(0, 0).UTC(),
+		},
+		{
+			name:   "keeps location",
+			t:      base.In(time.FixedZone("UTC+1", 3600)).Add(time.Millisecond),
+			length: time.Second,
+			want:   base.In(time.FixedZone("UTC+1", 3600)).Add(time.Second),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := RoundUp(tt.t, tt.length)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestOf(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, int64(0), Of(time.Unix(0, 9), 10))
+	require.Equal(t, int64(1), Of(time.Unix(0, 10), 10))
+	require.Equal(t, int64(-1), Of(time.Unix(0, -1), 10))
+	require.Equal(t, int64(-1), Of(time.Unix(0, -10), 10))
+}
@@ -0,0 +1,118 @@
+package timing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+var ErrJobNotFound = errors.New("job not found")
+
+// MisfirePolicy decides what happens to occurrences of a job that were missed
+// while no scheduler was running it.
+type MisfirePolicy int
+
+const (
+	// MisfireRunOnce performs the job once right away, no matter how many
+	// occurrences were missed.
+	MisfireRunOnce MisfirePolicy = iota
+	// MisfireRunAll performs every missed occurrence right away, oldest first.
+	MisfireRunAll
+	// MisfireSkip drops missed occurrences and waits for the next one.
+	MisfireSkip
+)
+
+// JobDefinition describes a job that occurs at Start and then every Interval
+// for as long as occurrences are before Until. A zero Interval describes a job
+// that occurs only once. LastRun holds the most recent occurrence that was
+// performed. Action and Params optionally name the job's action in an
+// ActionRegistry. Paused jobs are kept but not performed.
+type JobDefinition struct {
+	Name          string          `json:"name"`
+	Action        string          `json:"action,omitempty"`
+	Params        json.RawMessage `json:"params,omitempty"`
+	Start         time.Time       `json:"start"`
+	Interval      time.Duration   `json:"interval,omitempty"`
+	Until         *time.Time      `json:"until,omitempty"`
+	MisfirePolicy MisfirePolicy   `json:"misfirePolicy"`
+	LastRun       *time.Time      `json:"lastRun,omitempty"`
+	Paused        bool            `json:"paused,omitempty"`
+}
+
+// NextRun returns the first occurrence at or after notBefore that is later
+// than LastRun.
+func (j JobDefinition) NextRun(notBefore time.Time) (time.Time, bool) {
+	if j.LastRun != nil && !notBefore.After(*j.LastRun) {
+		notBefore = j.LastRun.Add(time.Nanosecond)
+	}
+
+	next := j.Start
+	if next.Before(notBefore) {
+		if j.Interval <= 0 {
+			return time.Time{}, false
+		}
+
+		periods := (notBefore.Sub(j.Start) + j.Interval - 1) / j.Interval
+		next = j.Start.Add(periods * j.Interval)
+	}
+
+	if j.Until != nil && !next.Before(*j.Until) {
+		return time.Time{}, false
+	}
+
+	return next, true
+}
+
+// MissedRuns returns all occurrences later than LastRun and before now.
+func (j JobDefinition) MissedRuns(now time.Time) []time.Time {
+	missed := make([]time.Time, 0)
+
+	for next, ok := j.NextRun(j.Start); ok && next.Before(now); next, ok = j.NextRun(next.Add(time.Nanosecond)) {
+		missed = append(missed, next)
+	}
+
+	return missed
+}
+
+// LastMissedRun returns the last of MissedRuns without computing the others,
+// which may be many after a long outage.
+func (j JobDefinition) LastMissedRun(now time.Time) (time.Time, bool) {
+	end := now
+	if j.Until != nil && j.Until.Before(end) {
+		end = *j.Until
+	}
+
+	if !j.Start.Before(end) {
+		return time.Time{}, false
+	}
+
+	last := j.Start
+	if j.Interval > 0 {
+		last = j.Start.Add((end.Sub(j.Start) - 1) / j.Interval * j.Interval)
+	}
+
+	if j.LastRun != nil && !last.After(*j.LastRun) {
+		return time.Time{}, false
+	}
+
+	return last, true
+}
+
+type JobStore interface {
+	SaveJob(definition JobDefinition) error
+	DeleteJob(name string) error
+	LoadJobs() ([]JobDefinition, error)
+	SetLastRun(name string, lastRun time.Time) error
+}
+
+type jobNameKey struct{}
+
+func WithJobName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, jobNameKey{}, name)
+}
+
+func JobNameFrom(ctx context.Context) string {
+	name, _ := ctx.Value(jobNameKey{}).(string)
+	return name
+}
@@ -0,0 +1,226 @@
+package timing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobDefinition_NextRun(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		definition JobDefinition
+		notBefore  time.Time
+		want       time.Time
+		wantOk     bool
+	}{
+		{
+			name:       "single job before start",
+			definition: JobDefinition{Start: start},
+			notBefore:  start.Add(-time.Hour),
+			want:       start,
+			wantOk:     true,
+		},
+		{
+			name:       "single job at start",
+			definition: JobDefinition{Start: start},
+			notBefore:  start,
+			want:       start,
+			wantOk:     true,
+		},
+		{
+			name:       "single job after start",
+			definition: JobDefinition{Start: start},
+			notBefore:  start.Add(time.Second),
+		},
+		{
+			name:       "single job already run",
+			definition: JobDefinition{Start: start, LastRun: &start},
+			notBefore:  start,
+		},
+		{
+			name:       "periodic job on occurrence",
+			definition: JobDefinition{Start: start, Interval: time.Hour},
+			notBefore:  start.Add(2 * time.Hour),
+			want:       start.Add(2 * time.Hour),
+			wantOk:     true,
+		},
+		{
+			name:       "periodic job between occurrences",
+			definition: JobDefinition{Start: start, Interval: time.Hour},
+			notBefore:  start.Add(90 * time.Minute),
+			want:       start.Add(2 * time.Hour),
+			wantOk:     true,
+		},
+		{
+			name:       "periodic job after last run",
+			definition: JobDefinition{Start: start, Interval: time.Hour, LastRun: ptr(start.Add(2 * time.Hour))},
+			notBefore:  start.Add(time.Hour),
+			want:       start.Add(3 * time.Hour),
+			wantOk:     true,
+		},
+		{
+			name:       "periodic job until reached",
+			definition: JobDefinition{Start: start, Interval: time.Hour, Until: ptr(start.Add(2 * time.Hour))},
+			notBefore:  start.Add(90 * time.Minute),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, gotOk := tt.definition.NextRun(tt.notBefore)
+			require.Equal(t, tt.wantOk, gotOk)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestJobDefinition_MissedRuns(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		definition JobDefinition
+		now        time.Time
+		want       []time.Time
+	}{
+		{
+			name:       "nothing missed",
+			definition: JobDefinition{Start: start, Interval: time.Hour},
+			now:        start,
+			want:       []time.Time{},
+		},
+		{
+			name:       "never run",
+			definition: JobDefinition{Start: start, Interval: time.Hour},
+			now:        start.Add(150 * time.Minute),
+			want:       []time.Time{start, start.Add(time.Hour), start.Add(2 * time.Hour)},
+		},
+		{
+			name:       "run before",
+			definition: JobDefinition{Start: start, Interval: time.Hour, LastRun: ptr(start.Add(time.Hour))},
+			now:        start.Add(3 * time.Hour),
+			want:       []time.Time{start.Add(2 * time.Hour)},
+		},
+		{
+			name:       "until reached",
+			definition: JobDefinition{Start: start, Interval: time.Hour, Until: ptr(start.Add(90 * time.Minute))},
+			now:        start.Add(5 * time.Hour),
+			want:       []time.Time{start, start.Add(time.Hour)},
+		},
+		{
+			name:       "single job",
+			definition: JobDefinition{Start: start},
+			now:        start.Add(time.Hour),
+			want:       []time.Time{start},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, tt.definition.MissedRuns(tt.now))
+		})
+	}
+}
+
+func TestJobDefinition_LastMissedRun(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		definition JobDefinition
+		now        time.Time
+		want       *time.Time
+	}{
+		{
+			name:       "nothing missed",
+			definition: JobDefinition{Start: start, Interval: time.Hour},
+			now:        start,
+		},
+		{
+			name:       "never run",
+			definition: JobDefinition{Start: start, Interval: time.Hour},
+			now:        start.Add(150 * time.Minute),
+			want:       ptr(start.Add(2 * time.Hour)),
+		},
+		{
+			name:       "now on an occurrence",
+			definition: JobDefinition{Start: start, Interval: time.Hour},
+			now:        start.Add(2 * time.Hour),
+			want:       ptr(start.Add(time.Hour)),
+		},
+		{
+			name:       "run before",
+			definition: JobDefinition{Start: start, Interval: time.Hour, LastRun: ptr(start.Add(time.Hour))},
+			now:        start.Add(3 * time.Hour),
+			want:       ptr(start.Add(2 * time.Hour)),
+		},
+		{
+			name:       "last one already run",
+			definition: JobDefinition{Start: start, Interval: time.Hour, LastRun: ptr(start.Add(2 * time.Hour))},
+			now:        start.Add(150 * time.Minute),
+		},
+		{
+			name:       "until reached",
+			definition: JobDefinition{Start: start, Interval: time.Hour, Until: ptr(start.Add(90 * time.Minute))},
+			now:        start.Add(5 * time.Hour),
+			want:       ptr(start.Add(time.Hour)),
+		},
+		{
+			name:       "until on an occurrence",
+			definition: JobDefinition{Start: start, Interval: time.Hour, Until: ptr(start.Add(2 * time.Hour))},
+			now:        start.Add(5 * time.Hour),
+			want:       ptr(start.Add(time.Hour)),
+		},
+		{
+			name:       "single job",
+			definition: JobDefinition{Start: start},
+			now:        start.Add(time.Hour),
+			want:       ptr(start),
+		},
+		{
+			name:       "long outage",
+			definition: JobDefinition{Start: start, Interval: time.Second},
+			now:        start.AddDate(10, 0, 0),
+			want:       ptr(start.AddDate(10, 0, 0).Add(-time.Second)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := tt.definition.LastMissedRun(tt.now)
+			if tt.want == nil {
+				require.False(t, ok)
+				return
+			}
+
+			require.True(t, ok)
+			require.Equal(t, *tt.want, got)
+		})
+	}
+}
+
+func TestJobName(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "", JobNameFrom(context.Background()))
+
+	ctx := WithJobName(context.Background(), "report")
+	require.Equal(t, "report", JobNameFrom(ctx))
+}
@@ -0,0 +1,164 @@
+package jobs
+
+import (
+	"encoding/json"
+	"errors"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/metamogul/timing"
+)
+
+var indexTemplate = template.Must(template.New("index").Funcs(template.FuncMap{"pathEscape": url.PathEscape}).Parse(`<!DOCTYPE html>
+<html>
+<head><title>Jobs</title></head>
+<body>
+<h1>Jobs</h1>
+<table>
+<tr><th>Name</th><th>Action</th><th>Interval</th><th>Next run</th><th>Last run</th><th>State</th><th></th></tr>
+{{- range .}}
+<tr>
+<td>{{.Name}}</td>
+<td>{{.Action}}</td>
+<td>{{if .Interval}}{{.Interval}}{{end}}</td>
+<td>{{with .NextRun}}{{.Format "2006-01-02 15:04:05 MST"}}{{end}}</td>
+<td>{{with .LastRun}}{{.Format "2006-01-02 15:04:05 MST"}}{{end}}</td>
+<td>{{if .Paused}}paused{{else}}active{{end}}</td>
+<td>
+{{- $path := pathEscape .Name}}
+<form method="post" action="jobs/{{$path}}/trigger"><button>Trigger</button></form>
+{{- if .Paused}}
+<form method="post" action="jobs/{{$path}}/resume"><button>Resume</button></form>
+{{- else}}
+<form method="post" action="jobs/{{$path}}/pause"><button>Pause</button></form>
+{{- end}}
+<form method="post" action="jobs/{{$path}}/cancel"><button>Cancel</button></form>
+</td>
+</tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
+
+// NewHandler returns an http.Handler to inspect and control the jobs of
+// scheduler. It serves an HTML overview at the root and a JSON API below
+// jobs/. Operations requested by browsers on behalf of other origins are
+// rejected. To mount it below a path, strip the prefix including a trailing
+// slash:
+//
+//	mux.Handle("/admin/jobs/", http.StripPrefix("/admin/jobs", jobs.NewHandler(scheduler)))
+func NewHandler(scheduler *Scheduler) http.Handler {
+	if scheduler == nil {
+		panic("scheduler can't be nil")
+	}
+
+	h := &handler{scheduler: scheduler}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", h.index)
+	mux.HandleFunc("GET /jobs", h.list)
+	mux.HandleFunc("GET /jobs/{name}", h.get)
+	mux.HandleFunc("DELETE /jobs/{name}", h.control(scheduler.Cancel))
+	mux.HandleFunc("POST /jobs/{name}/trigger", h.control(scheduler.Trigger))
+	mux.HandleFunc("POST /jobs/{name}/pause", h.control(scheduler.Pause))
+	mux.HandleFunc("POST /jobs/{name}/resume", h.control(scheduler.Resume))
+	mux.HandleFunc("POST /jobs/{name}/cancel", h.control(scheduler.Cancel))
+
+	return mux
+}
+
+type handler struct {
+	scheduler *Scheduler
+}
+
+func (h *handler) index(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = indexTemplate.Execute(w, h.scheduler.Jobs())
+}
+
+func (h *handler) list(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, h.scheduler.Jobs())
+}
+
+func (h *handler) get(w http.ResponseWriter, r *http.Request) {
+	status, err := h.scheduler.Job(r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, status)
+}
+
+// control performs an operation on a job. Requests from the HTML view are
+// redirected back to it, all others receive the job's new status, or no
+// content if the job is gone.
+func (h *handler) control(operation func(name string) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !sameOrigin(r) {
+			writeJSON(w, http.StatusForbidden, map[string]string{"error": "cross-origin request"})
+			return
+		}
+
+		name := r.PathValue("name")
+
+		if err := operation(name); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		if strings.Contains(r.Header.Get("Accept"), "text/html") {
+			// Relative, so the redirect works wherever the handler is mounted.
+			w.Header().Set("Location", "../../")
+			w.WriteHeader(http.StatusSeeOther)
+			return
+		}
+
+		status, err := h.scheduler.Job(name)
+		if errors.Is(err, timing.ErrJobNotFound) {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, status)
+	}
+}
+
+// sameOrigin tells whether a browser sent r from a page of the handler's own
+// origin, so that other sites can't make an admin's browser control jobs.
+// Requests without the headers browsers add, e.g. from scripts, pass.
+func sameOrigin(r *http.Request) bool {
+	switch r.Header.Get("Sec-Fetch-Site") {
+	case "same-origin", "none":
+		return true
+	case "":
+	default:
+		return false
+	}
+
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	originURL, err := url.Parse(origin)
+	return err == nil && originURL.Host == r.Host
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	statusCode := http.StatusInternalServerError
+	if errors.Is(err, timing.ErrJobNotFound) {
+		statusCode = http.StatusNotFound
+	}
+
+	writeJSON(w, statusCode, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,297 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/metamogul/timing"
+	"github.com/metamogul/timing/simulated_time"
+	"github.com/stretchr/testify/require"
+)
+
+func newHandlerUnderTest(t *testing.T) (*httptest.Server, *Scheduler, *simulated_time.SerialEventScheduler) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	eventScheduler := simulated_time.NewSerialEventScheduler(now)
+	scheduler := NewScheduler(eventScheduler, NewMemoryStore())
+
+	noop := timing.ActionFunc(func(timing.ActionContext) {})
+	require.NoError(t, scheduler.Register(timing.JobDefinition{Name: "report", Start: now.Add(time.Hour), Interval: time.Hour}, noop, context.Background()))
+	require.NoError(t, scheduler.Register(timing.JobDefinition{Name: "cleanup", Start: now.Add(time.Hour)}, noop, context.Background()))
+
+	mux := http.NewServeMux()
+	mux.Handle("/admin/", http.StripPrefix("/admin", NewHandler(scheduler)))
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server, scheduler, eventScheduler
+}
+
+func TestNewHandler(t *testing.T) {
+	t.Parallel()
+
+	require.Panics(t, func() {
+		_ = NewHandler(nil)
+	})
+}
+
+func TestHandler_index(t *testing.T) {
+	t.Parallel()
+
+	server, _, _ := newHandlerUnderTest(t)
+
+	response, err := http.Get(server.URL + "/admin/")
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	require.Contains(t, response.Header.Get("Content-Type"), "text/html")
+
+	body := readBody(t, response)
+	require.Contains(t, body, "<td>report</td>")
+	require.Contains(t, body, `action="jobs/report/pause"`)
+	require.Contains(t, body, "<td>cleanup</td>")
+}
+
+func TestHandler_list(t *testing.T) {
+	t.Parallel()
+
+	server, _, eventScheduler := newHandlerUnderTest(t)
+	eventScheduler.Forward(time.Hour)
+
+	response, err := http.Get(server.URL + "/admin/jobs")
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	require.Equal(t, "application/json", response.Header.Get("Content-Type"))
+
+	statuses := make([]JobStatus, 0)
+	require.NoError(t, json.NewDecoder(response.Body).Decode(&statuses))
+	require.Len(t, statuses, 2)
+	require.Equal(t, "cleanup", statuses[0].Name)
+	require.Nil(t, statuses[0].NextRun)
+	require.Equal(t, "report", statuses[1].Name)
+	require.True(t, eventScheduler.Now().Add(time.Hour).Equal(*statuses[1].NextRun))
+	require.True(t, eventScheduler.Now().Equal(*statuses[1].LastRun))
+}
+
+func TestHandler_get(t *testing.T) {
+	t.Parallel()
+
+	server, _, _ := newHandlerUnderTest(t)
+
+	response, err := http.Get(server.URL + "/admin/jobs/report")
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	require.Equal(t, http.StatusOK, response.StatusCode)
+
+	status := JobStatus{}
+	require.NoError(t, json.NewDecoder(response.Body).Decode(&status))
+	require.Equal(t, "report", status.Name)
+	require.Equal(t, time.Hour, status.Interval)
+
+	response, err = http.Get(server.URL + "/admin/jobs/unknown")
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	require.Equal(t, http.StatusNotFound, response.StatusCode)
+}
+
+func TestHandler_control(t *testing.T) {
+	t.Parallel()
+
+	server, scheduler, _ := newHandlerUnderTest(t)
+
+	response, err := http.Post(server.URL+"/admin/jobs/report/pause", "", nil)
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	status := JobStatus{}
+	require.NoError(t, json.NewDecoder(response.Body).Decode(&status))
+	require.True(t, status.Paused)
+
+	response, err = http.Post(server.URL+"/admin/jobs/report/resume", "", nil)
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	status, err = scheduler.Job("report")
+	require.NoError(t, err)
+	require.False(t, status.Paused)
+
+	response, err = http.Post(server.URL+"/admin/jobs/report/trigger", "", nil)
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	require.Equal(t, http.StatusOK, response.StatusCode)
+
+	request, err := http.NewRequest(http.MethodDelete, server.URL+"/admin/jobs/report", nil)
+	require.NoError(t, err)
+	response, err = http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	require.Equal(t, http.StatusNoContent, response.StatusCode)
+	require.Len(t, scheduler.Jobs(), 1)
+
+	response, err = http.Post(server.URL+"/admin/jobs/report/pause", "", nil)
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	require.Equal(t, http.StatusNotFound, response.StatusCode)
+}
+
+func TestHandler_control_fromHTMLView(t *testing.T) {
+	t.Parallel()
+
+	server, scheduler, _ := newHandlerUnderTest(t)
+
+	request, err := http.NewRequest(http.MethodPost, server.URL+"/admin/jobs/cleanup/cancel", nil)
+	require.NoError(t, err)
+	request.Header.Set("Accept", "text/html")
+
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+
+	response, err := client.Do(request)
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	require.Equal(t, http.StatusSeeOther, response.StatusCode)
+
+	location, err := response.Location()
+	require.NoError(t, err)
+	require.Equal(t, "/admin/", location.Path)
+
+	require.Len(t, scheduler.Jobs(), 1)
+}
+
+func TestHandler_control_crossOrigin(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		header     map[string]string
+		wantStatus int
+	}{
+		{
+			name:       "no browser headers",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "same origin",
+			header:     map[string]string{"Sec-Fetch-Site": "same-origin"},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "cross site",
+			header:     map[string]string{"Sec-Fetch-Site": "cross-site"},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "same site, other origin",
+			header:     map[string]string{"Sec-Fetch-Site": "same-site"},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "other origin",
+			header:     map[string]string{"Origin": "https://example.com"},
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server, scheduler, _ := newHandlerUnderTest(t)
+
+			request, err := http.NewRequest(http.MethodPost, server.URL+"/admin/jobs/report/pause", nil)
+			require.NoError(t, err)
+			for key, value := range tt.header {
+				request.Header.Set(key, value)
+			}
+
+			response, err := http.DefaultClient.Do(request)
+			require.NoError(t, err)
+			defer response.Body.Close()
+
+			require.Equal(t, tt.wantStatus, response.StatusCode)
+
+			status, err := scheduler.Job("report")
+			require.NoError(t, err)
+			require.Equal(t, tt.wantStatus == http.StatusOK, status.Paused)
+		})
+	}
+}
+
+func TestHandler_control_sameOriginFromHTMLView(t *testing.T) {
+	t.Parallel()
+
+	server, scheduler, _ := newHandlerUnderTest(t)
+
+	request, err := http.NewRequest(http.MethodPost, server.URL+"/admin/jobs/report/pause", nil)
+	require.NoError(t, err)
+	request.Header.Set("Origin", server.URL)
+
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	require.Equal(t, http.StatusOK, response.StatusCode)
+
+	status, err := scheduler.Job("report")
+	require.NoError(t, err)
+	require.True(t, status.Paused)
+}
+
+func TestHandler_escapesJobNames(t *testing.T) {
+	t.Parallel()
+
+	server, scheduler, _ := newHandlerUnderTest(t)
+
+	name := "reports/daily?full"
+	require.NoError(t, scheduler.Register(timing.JobDefinition{Name: name, Start: time.Date(2024, 1, 1, 13, 0, 0, 0, time.UTC)}, timing.ActionFunc(func(timing.ActionContext) {}), context.Background()))
+
+	response, err := http.Get(server.URL + "/admin/")
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	action := "jobs/reports%2fdaily%3ffull/pause"
+	require.Contains(t, strings.ToLower(readBody(t, response)), `action="`+action+`"`)
+
+	response, err = http.Post(server.URL+"/admin/"+action, "", nil)
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	require.Equal(t, http.StatusOK, response.StatusCode)
+
+	status, err := scheduler.Job(name)
+	require.NoError(t, err)
+	require.True(t, status.Paused)
+}
+
+func readBody(t *testing.T, response *http.Response) string {
+	body := make([]byte, 0)
+	buffer := make([]byte, 1024)
+
+	for {
+		n, err := response.Body.Read(buffer)
+		body = append(body, buffer[:n]...)
+		if err != nil {
+			break
+		}
+	}
+
+	return string(body)
+}
@@ -0,0 +1,475 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/metamogul/timing"
+	"github.com/metamogul/timing/simulated_time"
+)
+
+var (
+	ErrJobExists         = errors.New("job already registered")
+	ErrJobActionNotFound = errors.New("no action for job")
+)
+
+// Scheduler runs named jobs on top of any timing.EventScheduler and keeps
+// their definitions and last runs in a timing.JobStore, so a new Scheduler
+// over the same store can pick them up again after a restart.
+type Scheduler struct {
+	// ErrorHandler receives errors from the store that occur while a job is
+	// performed. They are dropped if it is nil.
+	ErrorHandler func(error)
+
+	// Registry resolves the actions of jobs that are defined by name. It
+	// defaults to timing.DefaultActionRegistry.
+	Registry *timing.ActionRegistry
+
+	scheduler timing.EventScheduler
+	store     timing.JobStore
+
+	mu   sync.Mutex
+	jobs map[string]*job
+}
+
+type job struct {
+	definition timing.JobDefinition
+	action     timing.Action
+	nextRun    *time.Time
+	removed    bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// JobStatus describes the current state of a registered job.
+type JobStatus struct {
+	Name     string        `json:"name"`
+	Action   string        `json:"action,omitempty"`
+	Interval time.Duration `json:"interval,omitempty"`
+	NextRun  *time.Time    `json:"nextRun,omitempty"`
+	LastRun  *time.Time    `json:"lastRun,omitempty"`
+	Paused   bool          `json:"paused"`
+}
+
+func NewScheduler(scheduler timing.EventScheduler, store timing.JobStore) *Scheduler {
+	if scheduler == nil {
+		panic("scheduler can't be nil")
+	}
+
+	if store == nil {
+		panic("store can't be nil")
+	}
+
+	return &Scheduler{
+		scheduler: scheduler,
+		store:     store,
+		jobs:      make(map[string]*job),
+	}
+}
+
+// Register saves the job definition to the store and schedules its next
+// occurrence. If action is a *timing.NamedAction, its name and parameters are
+// stored with the definition.
+func (s *Scheduler) Register(definition timing.JobDefinition, action timing.Action, ctx context.Context) error {
+	if namedAction, isNamed := action.(*timing.NamedAction); isNamed && definition.Action == "" {
+		definition.Action = namedAction.Name
+		definition.Params = namedAction.Params
+	}
+
+	if definition.Name == "" {
+		return errors.New("job name can't be empty")
+	}
+
+	if definition.Interval < 0 {
+		return errors.New("job interval can't be negative")
+	}
+
+	if action == nil {
+		panic("action can't be nil")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.jobs[definition.Name]; exists {
+		return fmt.Errorf("%w: %s", ErrJobExists, definition.Name)
+	}
+
+	if err := s.store.SaveJob(definition); err != nil {
+		return err
+	}
+
+	j := s.addJob(definition, action, ctx)
+	s.scheduleNext(j, s.scheduler.Now())
+
+	return nil
+}
+
+// RegisterNamed registers a job whose action is created from the
+// definition's Action and Params through the registry.
+func (s *Scheduler) RegisterNamed(definition timing.JobDefinition, ctx context.Context) error {
+	action, err := s.registry().New(definition.Action, definition.Params)
+	if err != nil {
+		return err
+	}
+
+	return s.Register(definition, action, ctx)
+}
+
+// Restore loads all jobs from the store, applies their misfire policy to the
+// occurrences missed since their last run and schedules their next
+// occurrence. Actions are looked up by job name in actions first and then by
+// action name in the registry. Jobs without an action are skipped and
+// reported in the returned error.
+func (s *Scheduler) Restore(actions map[string]timing.Action, ctx context.Context) error {
+	definitions, err := s.store.LoadJobs()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.scheduler.Now()
+	errs := make([]error, 0)
+
+	for _, definition := range definitions {
+		if _, exists := s.jobs[definition.Name]; exists {
+			errs = append(errs, fmt.Errorf("%w: %s", ErrJobExists, definition.Name))
+			continue
+		}
+
+		action, err := s.action(definition, actions)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		j := s.addJob(definition, action, ctx)
+		if definition.Paused {
+			continue
+		}
+
+		s.performMissed(j, now)
+		s.scheduleNext(j, now)
+	}
+
+	return errors.Join(errs...)
+}
+
+// Jobs returns the status of all registered jobs, sorted by name.
+func (s *Scheduler) Jobs() []JobStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]JobStatus, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		statuses = append(statuses, j.status())
+	}
+
+	slices.SortFunc(statuses, func(a, b JobStatus) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return statuses
+}
+
+func (s *Scheduler) Job(name string) (JobStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, err := s.job(name)
+	if err != nil {
+		return JobStatus{}, err
+	}
+
+	return j.status(), nil
+}
+
+// Trigger performs a job right away, in addition to its regular occurrences.
+func (s *Scheduler) Trigger(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, err := s.job(name)
+	if err != nil {
+		return err
+	}
+
+	s.scheduler.PerformNow(j.action, j.ctx)
+
+	return nil
+}
+
+// Pause stops performing a job until it is resumed. The paused state is
+// persisted in the store.
+func (s *Scheduler) Pause(name string) error {
+	return s.setPaused(name, true)
+}
+
+// Resume continues a paused job with its next occurrence. Occurrences missed
+// while the job was paused are skipped.
+func (s *Scheduler) Resume(name string) error {
+	return s.setPaused(name, false)
+}
+
+// Cancel stops a job and removes it from the store.
+func (s *Scheduler) Cancel(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, err := s.job(name)
+	if err != nil {
+		return err
+	}
+
+	if err = s.store.DeleteJob(name); err != nil {
+		return err
+	}
+
+	s.unschedule(j)
+	j.removed = true
+	delete(s.jobs, name)
+
+	return nil
+}
+
+func (s *Scheduler) setPaused(name string, paused bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, err := s.job(name)
+	if err != nil {
+		return err
+	}
+
+	if j.definition.Paused == paused {
+		return nil
+	}
+
+	definition := j.definition
+	definition.Paused = paused
+	if err = s.store.SaveJob(definition); err != nil {
+		return err
+	}
+	j.definition = definition
+
+	if paused {
+		s.unschedule(j)
+	} else {
+		s.scheduleNext(j, s.scheduler.Now())
+	}
+
+	return nil
+}
+
+func (s *Scheduler) job(name string) (*job, error) {
+	j, found := s.jobs[name]
+	if !found {
+		return nil, fmt.Errorf("%w: %s", timing.ErrJobNotFound, name)
+	}
+
+	return j, nil
+}
+
+func (s *Scheduler) unschedule(j *job) {
+	if j.cancel != nil {
+		j.cancel()
+		j.cancel = nil
+	}
+
+	j.nextRun = nil
+}
+
+func (s *Scheduler) action(definition timing.JobDefinition, actions map[string]timing.Action) (timing.Action, error) {
+	if action, found := actions[definition.Name]; found {
+		return action, nil
+	}
+
+	if definition.Action == "" {
+		return nil, fmt.Errorf("%w: %s", ErrJobActionNotFound, definition.Name)
+	}
+
+	action, err := s.registry().New(definition.Action, definition.Params)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrJobActionNotFound, definition.Name, err)
+	}
+
+	return action, nil
+}
+
+func (s *Scheduler) registry() *timing.ActionRegistry {
+	if s.Registry == nil {
+		return timing.DefaultActionRegistry
+	}
+
+	return s.Registry
+}
+
+func (s *Scheduler) addJob(definition timing.JobDefinition, action timing.Action, ctx context.Context) *job {
+	j := &job{
+		definition: definition,
+		action:     action,
+		ctx:        timing.WithJobName(ctx, definition.Name),
+	}
+	s.jobs[definition.Name] = j
+
+	return j
+}
+
+func (s *Scheduler) performMissed(j *job, now time.Time) {
+	var missed []time.Time
+
+	switch j.definition.MisfirePolicy {
+	case timing.MisfireRunAll:
+		missed = j.definition.MissedRuns(now)
+	case timing.MisfireRunOnce:
+		if last, ok := j.definition.LastMissedRun(now); ok {
+			missed = []time.Time{last}
+		}
+	}
+
+	if len(missed) == 0 {
+		return
+	}
+
+	s.scheduler.PerformNow(&missedOccurrences{scheduler: s, job: j, occurrences: missed}, j.ctx)
+}
+
+// scheduleNext schedules the first occurrence of j at or after notBefore.
+// It is wrapped in a simulated_time.SchedulingAction, so an
+// AsyncEventScheduler waits for it to schedule its successor before it moves
+// the clock on.
+func (s *Scheduler) scheduleNext(j *job, notBefore time.Time) {
+	j.cancel, j.nextRun = nil, nil
+
+	next, ok := j.definition.NextRun(notBefore)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(j.ctx)
+	j.cancel = cancel
+	j.nextRun = &next
+
+	s.scheduler.PerformAfter(
+		simulated_time.NewSchedulingAction(&occurrence{scheduler: s, job: j, scheduledTime: next}),
+		max(next.Sub(s.scheduler.Now()), 0),
+		ctx,
+	)
+}
+
+func (s *Scheduler) recordRun(j *job, scheduledTime time.Time) {
+	if j.definition.LastRun != nil && !scheduledTime.After(*j.definition.LastRun) {
+		return
+	}
+
+	j.definition.LastRun = &scheduledTime
+
+	if err := s.store.SetLastRun(j.definition.Name, scheduledTime); err != nil && s.ErrorHandler != nil {
+		s.ErrorHandler(err)
+	}
+}
+
+type occurrence struct {
+	scheduler     *Scheduler
+	job           *job
+	scheduledTime time.Time
+}
+
+// Perform schedules the job's next occurrence relative to this one's
+// scheduled time, rather than to the time it is performed at, before it
+// performs the job's action.
+func (o *occurrence) Perform(ctx timing.ActionContext) {
+	o.scheduler.mu.Lock()
+
+	if o.job.removed || o.job.definition.Paused {
+		o.scheduler.mu.Unlock()
+		ctx.DoneSchedulingNewEvents()
+		return
+	}
+
+	o.scheduler.recordRun(o.job, o.scheduledTime)
+	o.scheduler.scheduleNext(o.job, o.scheduledTime)
+	o.scheduler.mu.Unlock()
+	ctx.DoneSchedulingNewEvents()
+
+	o.job.action.Perform(o.job.actionContext(ctx, o.scheduledTime))
+}
+
+// actionContext returns the ActionContext of the job's occurrence at
+// scheduledTime, performed by the event of ctx.
+func (j *job) actionContext(ctx timing.ActionContext, scheduledTime time.Time) timing.ActionContext {
+	metadata := ctx.Metadata()
+	metadata.ScheduledTime = scheduledTime
+	metadata.Occurrence = 1
+	if j.definition.Interval > 0 {
+		metadata.Occurrence += int(scheduledTime.Sub(j.definition.Start) / j.definition.Interval)
+	}
+
+	return &occurrenceActionContext{
+		ActionContext: timing.DeriveActionContext(ctx, ctx),
+		metadata:      metadata,
+	}
+}
+
+// occurrenceActionContext describes an occurrence of a job rather than the
+// event it is performed by, which differ for missed occurrences.
+type occurrenceActionContext struct {
+	timing.ActionContext
+	metadata timing.EventMetadata
+}
+
+func (o *occurrenceActionContext) Metadata() timing.EventMetadata {
+	return o.metadata
+}
+
+func (o *occurrenceActionContext) ScheduledTime() time.Time {
+	return o.metadata.ScheduledTime
+}
+
+func (o *occurrenceActionContext) Occurrence() int {
+	return o.metadata.Occurrence
+}
+
+func (j *job) status() JobStatus {
+	return JobStatus{
+		Name:     j.definition.Name,
+		Action:   j.definition.Action,
+		Interval: j.definition.Interval,
+		NextRun:  copyTime(j.nextRun),
+		LastRun:  copyTime(j.definition.LastRun),
+		Paused:   j.definition.Paused,
+	}
+}
+
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	c := *t
+	return &c
+}
+
+type missedOccurrences struct {
+	scheduler   *Scheduler
+	job         *job
+	occurrences []time.Time
+}
+
+func (m *missedOccurrences) Perform(ctx timing.ActionContext) {
+	for _, scheduledTime := range m.occurrences {
+		m.job.action.Perform(m.job.actionContext(ctx, scheduledTime))
+
+		m.scheduler.mu.Lock()
+		m.scheduler.recordRun(m.job, scheduledTime)
+		m.scheduler.mu.Unlock()
+	}
+}
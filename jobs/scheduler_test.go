@@ -0,0 +1,496 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/metamogul/timing"
+	"github.com/metamogul/timing/simulated_time"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewScheduler(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	require.Panics(t, func() {
+		_ = NewScheduler(nil, NewMemoryStore())
+	})
+
+	require.Panics(t, func() {
+		_ = NewScheduler(simulated_time.NewSerialEventScheduler(now), nil)
+	})
+
+	schedulerUnderTest := NewScheduler(simulated_time.NewSerialEventScheduler(now), NewMemoryStore())
+	require.NotNil(t, schedulerUnderTest)
+	require.NotNil(t, schedulerUnderTest.jobs)
+}
+
+func TestScheduler_Register(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	eventScheduler := simulated_time.NewSerialEventScheduler(now)
+	store := NewMemoryStore()
+	schedulerUnderTest := NewScheduler(eventScheduler, store)
+
+	eventTimes := make([]time.Time, 0)
+
+	mockAction := timing.NewMockAction(t)
+	mockAction.EXPECT().
+		Perform(mock.Anything).
+		Run(func(ctx timing.ActionContext) {
+			require.Equal(t, "report", timing.JobNameFrom(ctx))
+			eventTimes = append(eventTimes, ctx.Clock().Now())
+		}).
+		Times(3)
+
+	definition := timing.JobDefinition{Name: "report", Start: now.Add(time.Hour), Interval: time.Hour}
+	require.NoError(t, schedulerUnderTest.Register(definition, mockAction, context.Background()))
+
+	eventScheduler.Forward(3*time.Hour + 30*time.Minute)
+
+	require.Equal(t, []time.Time{now.Add(time.Hour), now.Add(2 * time.Hour), now.Add(3 * time.Hour)}, eventTimes)
+
+	definitions, err := store.LoadJobs()
+	require.NoError(t, err)
+	require.Len(t, definitions, 1)
+	require.Equal(t, now.Add(3*time.Hour), *definitions[0].LastRun)
+}
+
+func TestScheduler_Register_asyncEventScheduler(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	eventScheduler := simulated_time.NewAsyncEventScheduler(now)
+	schedulerUnderTest := NewScheduler(eventScheduler, NewMemoryStore())
+
+	var mu sync.Mutex
+	eventTimes := make([]time.Time, 0)
+
+	mockAction := timing.NewMockAction(t)
+	mockAction.EXPECT().
+		Perform(mock.Anything).
+		Run(func(ctx timing.ActionContext) {
+			mu.Lock()
+			defer mu.Unlock()
+			eventTimes = append(eventTimes, ctx.ScheduledTime())
+		}).
+		Times(5)
+
+	definition := timing.JobDefinition{Name: "report", Start: now.Add(time.Hour), Interval: time.Hour}
+	require.NoError(t, schedulerUnderTest.Register(definition, mockAction, context.Background()))
+
+	eventScheduler.Forward(5*time.Hour + time.Minute)
+
+	require.Equal(t, []time.Time{
+		now.Add(time.Hour), now.Add(2 * time.Hour), now.Add(3 * time.Hour), now.Add(4 * time.Hour), now.Add(5 * time.Hour),
+	}, eventTimes)
+
+	status, err := schedulerUnderTest.Job("report")
+	require.NoError(t, err)
+	require.Equal(t, now.Add(6*time.Hour), *status.NextRun)
+}
+
+func TestScheduler_Register_followUp(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	eventScheduler := simulated_time.NewSerialEventScheduler(now)
+	schedulerUnderTest := NewScheduler(eventScheduler, NewMemoryStore())
+
+	followUp := timing.NewMockAction(t)
+	followUp.EXPECT().Perform(mock.Anything).Once()
+
+	definition := timing.JobDefinition{Name: "report", Start: now.Add(time.Hour)}
+	action := timing.ActionFunc(func(ctx timing.ActionContext) {
+		eventScheduler.PerformAfter(followUp, time.Minute, ctx)
+	})
+	require.NoError(t, schedulerUnderTest.Register(definition, action, context.Background()))
+
+	eventScheduler.Forward(2 * time.Hour)
+}
+
+func TestScheduler_Register_invalid(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	schedulerUnderTest := NewScheduler(simulated_time.NewSerialEventScheduler(now), NewMemoryStore())
+
+	err := schedulerUnderTest.Register(timing.JobDefinition{Start: now}, timing.NewMockAction(t), context.Background())
+	require.Error(t, err)
+
+	err = schedulerUnderTest.Register(timing.JobDefinition{Name: "report", Interval: -time.Second}, timing.NewMockAction(t), context.Background())
+	require.Error(t, err)
+
+	require.Panics(t, func() {
+		_ = schedulerUnderTest.Register(timing.JobDefinition{Name: "report"}, nil, context.Background())
+	})
+
+	err = schedulerUnderTest.Register(timing.JobDefinition{Name: "report", Start: now.Add(time.Hour)}, timing.NewMockAction(t), context.Background())
+	require.NoError(t, err)
+
+	err = schedulerUnderTest.Register(timing.JobDefinition{Name: "report", Start: now.Add(time.Hour)}, timing.NewMockAction(t), context.Background())
+	require.ErrorIs(t, err, ErrJobExists)
+}
+
+func TestScheduler_Restore(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		misfirePolicy  timing.MisfirePolicy
+		wantEventTimes []time.Time
+		wantScheduled  []time.Time
+		wantOccurrence []int
+		wantLastRun    time.Time
+	}{
+		{
+			name:          "run once",
+			misfirePolicy: timing.MisfireRunOnce,
+			wantEventTimes: []time.Time{
+				start.Add(5*time.Hour + 30*time.Minute),
+				start.Add(6 * time.Hour),
+			},
+			wantScheduled:  []time.Time{start.Add(5 * time.Hour), start.Add(6 * time.Hour)},
+			wantOccurrence: []int{5, 6},
+			wantLastRun:    start.Add(6 * time.Hour),
+		},
+		{
+			name:          "run all",
+			misfirePolicy: timing.MisfireRunAll,
+			wantEventTimes: []time.Time{
+				start.Add(5*time.Hour + 30*time.Minute),
+				start.Add(5*time.Hour + 30*time.Minute),
+				start.Add(5*time.Hour + 30*time.Minute),
+				start.Add(6 * time.Hour),
+			},
+			wantScheduled:  []time.Time{start.Add(3 * time.Hour), start.Add(4 * time.Hour), start.Add(5 * time.Hour), start.Add(6 * time.Hour)},
+			wantOccurrence: []int{3, 4, 5, 6},
+			wantLastRun:    start.Add(6 * time.Hour),
+		},
+		{
+			name:          "skip",
+			misfirePolicy: timing.MisfireSkip,
+			wantEventTimes: []time.Time{
+				start.Add(6 * time.Hour),
+			},
+			wantScheduled:  []time.Time{start.Add(6 * time.Hour)},
+			wantOccurrence: []int{6},
+			wantLastRun:    start.Add(6 * time.Hour),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := NewMemoryStore()
+
+			definition := timing.JobDefinition{
+				Name:          "report",
+				Start:         start.Add(time.Hour),
+				Interval:      time.Hour,
+				MisfirePolicy: tt.misfirePolicy,
+			}
+
+			firstRunScheduler := simulated_time.NewSerialEventScheduler(start)
+			firstRunAction := timing.ActionFunc(func(timing.ActionContext) {})
+			require.NoError(t, NewScheduler(firstRunScheduler, store).Register(definition, firstRunAction, context.Background()))
+			firstRunScheduler.Forward(2*time.Hour + 30*time.Minute)
+
+			eventTimes, scheduled, occurrences := make([]time.Time, 0), make([]time.Time, 0), make([]int, 0)
+			secondRunAction := timing.ActionFunc(func(ctx timing.ActionContext) {
+				eventTimes = append(eventTimes, ctx.Clock().Now())
+				scheduled = append(scheduled, ctx.ScheduledTime())
+				occurrences = append(occurrences, ctx.Metadata().Occurrence)
+				require.Equal(t, ctx.Metadata().Occurrence, ctx.Occurrence())
+			})
+
+			secondRunScheduler := simulated_time.NewSerialEventScheduler(start.Add(5*time.Hour + 30*time.Minute))
+			err := NewScheduler(secondRunScheduler, store).Restore(map[string]timing.Action{"report": secondRunAction}, context.Background())
+			require.NoError(t, err)
+
+			secondRunScheduler.Forward(time.Hour)
+
+			require.Equal(t, tt.wantEventTimes, eventTimes)
+			require.Equal(t, tt.wantScheduled, scheduled)
+			require.Equal(t, tt.wantOccurrence, occurrences)
+
+			definitions, err := store.LoadJobs()
+			require.NoError(t, err)
+			require.Equal(t, tt.wantLastRun, *definitions[0].LastRun)
+		})
+	}
+}
+
+func TestScheduler_Restore_missingAction(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	store := NewMemoryStore()
+	require.NoError(t, store.SaveJob(timing.JobDefinition{Name: "report", Start: now, Interval: time.Hour}))
+	require.NoError(t, store.SaveJob(timing.JobDefinition{Name: "cleanup", Start: now, Interval: time.Hour}))
+
+	eventScheduler := simulated_time.NewSerialEventScheduler(now)
+	schedulerUnderTest := NewScheduler(eventScheduler, store)
+
+	mockAction := timing.NewMockAction(t)
+	mockAction.EXPECT().
+		Perform(mock.Anything).
+		Twice()
+
+	err := schedulerUnderTest.Restore(map[string]timing.Action{"cleanup": mockAction}, context.Background())
+	require.ErrorIs(t, err, ErrJobActionNotFound)
+
+	eventScheduler.Forward(time.Hour)
+
+	err = schedulerUnderTest.Restore(map[string]timing.Action{"cleanup": mockAction}, context.Background())
+	require.ErrorIs(t, err, ErrJobExists)
+}
+
+func TestScheduler_ErrorHandler(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	store := NewMemoryStore()
+	eventScheduler := simulated_time.NewSerialEventScheduler(now)
+	schedulerUnderTest := NewScheduler(eventScheduler, store)
+
+	errs := make([]error, 0)
+	schedulerUnderTest.ErrorHandler = func(err error) { errs = append(errs, err) }
+
+	require.NoError(t, schedulerUnderTest.Register(timing.JobDefinition{Name: "report", Start: now.Add(time.Hour)}, timing.ActionFunc(func(timing.ActionContext) {}), context.Background()))
+	require.NoError(t, store.DeleteJob("report"))
+
+	eventScheduler.Forward(time.Hour)
+
+	require.Len(t, errs, 1)
+	require.True(t, errors.Is(errs[0], timing.ErrJobNotFound))
+}
+
+func TestScheduler_RegisterNamed(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	registry := timing.NewActionRegistry()
+
+	eventTimes := make([]time.Time, 0)
+	gotParams := make([]string, 0)
+	registry.Register("report", func(params json.RawMessage) (timing.Action, error) {
+		return timing.ActionFunc(func(ctx timing.ActionContext) {
+			eventTimes = append(eventTimes, ctx.Clock().Now())
+			gotParams = append(gotParams, string(params))
+		}), nil
+	})
+
+	store := NewMemoryStore()
+	definition := timing.JobDefinition{
+		Name:     "daily-report",
+		Action:   "report",
+		Params:   json.RawMessage(`{"format":"pdf"}`),
+		Start:    now.Add(time.Hour),
+		Interval: time.Hour,
+	}
+
+	firstRunScheduler := simulated_time.NewSerialEventScheduler(now)
+	firstRunJobs := NewScheduler(firstRunScheduler, store)
+	firstRunJobs.Registry = registry
+
+	require.ErrorIs(t, firstRunJobs.RegisterNamed(timing.JobDefinition{Name: "unknown", Action: "unknown"}, context.Background()), timing.ErrActionNotRegistered)
+	require.NoError(t, firstRunJobs.RegisterNamed(definition, context.Background()))
+	firstRunScheduler.Forward(time.Hour)
+
+	secondRunScheduler := simulated_time.NewSerialEventScheduler(now.Add(90 * time.Minute))
+	secondRunJobs := NewScheduler(secondRunScheduler, store)
+	secondRunJobs.Registry = registry
+
+	require.NoError(t, secondRunJobs.Restore(nil, context.Background()))
+	secondRunScheduler.Forward(30 * time.Minute)
+
+	require.Equal(t, []time.Time{now.Add(time.Hour), now.Add(2 * time.Hour)}, eventTimes)
+	require.Equal(t, []string{`{"format":"pdf"}`, `{"format":"pdf"}`}, gotParams)
+}
+
+func TestScheduler_Register_namedAction(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	store := NewMemoryStore()
+	schedulerUnderTest := NewScheduler(simulated_time.NewSerialEventScheduler(now), store)
+
+	namedAction := &timing.NamedAction{Name: "report", Params: json.RawMessage(`{}`), Action: timing.NewMockAction(t)}
+	require.NoError(t, schedulerUnderTest.Register(timing.JobDefinition{Name: "daily-report", Start: now.Add(time.Hour)}, namedAction, context.Background()))
+
+	definitions, err := store.LoadJobs()
+	require.NoError(t, err)
+	require.Equal(t, "report", definitions[0].Action)
+	require.Equal(t, json.RawMessage(`{}`), definitions[0].Params)
+}
+
+func TestScheduler_Restore_unknownNamedAction(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	store := NewMemoryStore()
+	require.NoError(t, store.SaveJob(timing.JobDefinition{Name: "daily-report", Action: "report", Start: now}))
+
+	schedulerUnderTest := NewScheduler(simulated_time.NewSerialEventScheduler(now), store)
+	schedulerUnderTest.Registry = timing.NewActionRegistry()
+
+	err := schedulerUnderTest.Restore(nil, context.Background())
+	require.ErrorIs(t, err, ErrJobActionNotFound)
+	require.ErrorIs(t, err, timing.ErrActionNotRegistered)
+}
+
+func TestScheduler_Jobs(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	eventScheduler := simulated_time.NewSerialEventScheduler(now)
+	schedulerUnderTest := NewScheduler(eventScheduler, NewMemoryStore())
+
+	noop := timing.ActionFunc(func(timing.ActionContext) {})
+	require.NoError(t, schedulerUnderTest.Register(timing.JobDefinition{Name: "report", Start: now, Interval: time.Hour}, noop, context.Background()))
+	require.NoError(t, schedulerUnderTest.Register(timing.JobDefinition{Name: "cleanup", Start: now.Add(30 * time.Minute)}, noop, context.Background()))
+
+	eventScheduler.Forward(time.Hour)
+
+	require.Equal(t, []JobStatus{
+		{
+			Name:    "cleanup",
+			LastRun: ptr(now.Add(30 * time.Minute)),
+		},
+		{
+			Name:     "report",
+			Interval: time.Hour,
+			NextRun:  ptr(now.Add(2 * time.Hour)),
+			LastRun:  ptr(now.Add(time.Hour)),
+		},
+	}, schedulerUnderTest.Jobs())
+
+	_, err := schedulerUnderTest.Job("unknown")
+	require.ErrorIs(t, err, timing.ErrJobNotFound)
+}
+
+func TestScheduler_Trigger(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	eventScheduler := simulated_time.NewSerialEventScheduler(now)
+	schedulerUnderTest := NewScheduler(eventScheduler, NewMemoryStore())
+
+	eventTimes := make([]time.Time, 0)
+	action := timing.ActionFunc(func(ctx timing.ActionContext) {
+		eventTimes = append(eventTimes, ctx.Clock().Now())
+	})
+	require.NoError(t, schedulerUnderTest.Register(timing.JobDefinition{Name: "report", Start: now.Add(time.Hour)}, action, context.Background()))
+
+	eventScheduler.Forward(10 * time.Minute)
+	require.NoError(t, schedulerUnderTest.Trigger("report"))
+	require.ErrorIs(t, schedulerUnderTest.Trigger("unknown"), timing.ErrJobNotFound)
+	eventScheduler.Forward(time.Hour)
+
+	require.Equal(t, []time.Time{now.Add(10 * time.Minute), now.Add(time.Hour)}, eventTimes)
+}
+
+func TestScheduler_PauseResume(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	store := NewMemoryStore()
+	eventScheduler := simulated_time.NewSerialEventScheduler(now)
+	schedulerUnderTest := NewScheduler(eventScheduler, store)
+
+	eventTimes := make([]time.Time, 0)
+	action := timing.ActionFunc(func(ctx timing.ActionContext) {
+		eventTimes = append(eventTimes, ctx.Clock().Now())
+	})
+	require.NoError(t, schedulerUnderTest.Register(timing.JobDefinition{Name: "report", Start: now.Add(time.Hour), Interval: time.Hour}, action, context.Background()))
+
+	eventScheduler.Forward(90 * time.Minute)
+
+	require.NoError(t, schedulerUnderTest.Pause("report"))
+	require.NoError(t, schedulerUnderTest.Pause("report"))
+	require.ErrorIs(t, schedulerUnderTest.Pause("unknown"), timing.ErrJobNotFound)
+
+	status, err := schedulerUnderTest.Job("report")
+	require.NoError(t, err)
+	require.True(t, status.Paused)
+	require.Nil(t, status.NextRun)
+
+	definitions, err := store.LoadJobs()
+	require.NoError(t, err)
+	require.True(t, definitions[0].Paused)
+
+	eventScheduler.Forward(2 * time.Hour)
+
+	require.NoError(t, schedulerUnderTest.Resume("report"))
+	require.ErrorIs(t, schedulerUnderTest.Resume("unknown"), timing.ErrJobNotFound)
+
+	eventScheduler.Forward(time.Hour)
+
+	require.Equal(t, []time.Time{now.Add(time.Hour), now.Add(4 * time.Hour)}, eventTimes)
+}
+
+func TestScheduler_Restore_paused(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	store := NewMemoryStore()
+	require.NoError(t, store.SaveJob(timing.JobDefinition{Name: "report", Start: now, Interval: time.Hour, Paused: true}))
+
+	eventScheduler := simulated_time.NewSerialEventScheduler(now.Add(90 * time.Minute))
+	schedulerUnderTest := NewScheduler(eventScheduler, store)
+
+	require.NoError(t, schedulerUnderTest.Restore(map[string]timing.Action{"report": timing.NewMockAction(t)}, context.Background()))
+	eventScheduler.Forward(2 * time.Hour)
+
+	status, err := schedulerUnderTest.Job("report")
+	require.NoError(t, err)
+	require.True(t, status.Paused)
+}
+
+func TestScheduler_Cancel(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	store := NewMemoryStore()
+	eventScheduler := simulated_time.NewSerialEventScheduler(now)
+	schedulerUnderTest := NewScheduler(eventScheduler, store)
+
+	require.NoError(t, schedulerUnderTest.Register(timing.JobDefinition{Name: "report", Start: now.Add(time.Hour), Interval: time.Hour}, timing.NewMockAction(t), context.Background()))
+
+	require.NoError(t, schedulerUnderTest.Cancel("report"))
+	require.ErrorIs(t, schedulerUnderTest.Cancel("report"), timing.ErrJobNotFound)
+
+	eventScheduler.Forward(3 * time.Hour)
+
+	require.Empty(t, schedulerUnderTest.Jobs())
+
+	definitions, err := store.LoadJobs()
+	require.NoError(t, err)
+	require.Empty(t, definitions)
+}
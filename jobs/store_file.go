@@ -0,0 +1,144 @@
+package jobs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/metamogul/timing"
+)
+
+// FileStore keeps job definitions in a JSON file. Every change rewrites the
+// file atomically by renaming a temporary file over it.
+type FileStore struct {
+	path string
+
+	mu   sync.RWMutex
+	jobs map[string]timing.JobDefinition
+}
+
+func NewFileStore(path string) (*FileStore, error) {
+	f := &FileStore{
+		path: path,
+		jobs: make(map[string]timing.JobDefinition),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	definitions := make([]timing.JobDefinition, 0)
+	if err = json.Unmarshal(data, &definitions); err != nil {
+		return nil, fmt.Errorf("reading job store %s: %w", path, err)
+	}
+
+	for _, definition := range definitions {
+		f.jobs[definition.Name] = definition
+	}
+
+	return f, nil
+}
+
+func (f *FileStore) SaveJob(definition timing.JobDefinition) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	previous, existed := f.jobs[definition.Name]
+	f.jobs[definition.Name] = copyDefinition(definition)
+
+	if err := f.write(); err != nil {
+		if existed {
+			f.jobs[definition.Name] = previous
+		} else {
+			delete(f.jobs, definition.Name)
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (f *FileStore) DeleteJob(name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	previous, found := f.jobs[name]
+	if !found {
+		return fmt.Errorf("%w: %s", timing.ErrJobNotFound, name)
+	}
+
+	delete(f.jobs, name)
+
+	if err := f.write(); err != nil {
+		f.jobs[name] = previous
+		return err
+	}
+
+	return nil
+}
+
+func (f *FileStore) LoadJobs() ([]timing.JobDefinition, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return sortedDefinitions(f.jobs), nil
+}
+
+func (f *FileStore) SetLastRun(name string, lastRun time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	previous, found := f.jobs[name]
+	if !found {
+		return fmt.Errorf("%w: %s", timing.ErrJobNotFound, name)
+	}
+
+	definition := copyDefinition(previous)
+	definition.LastRun = &lastRun
+	f.jobs[name] = definition
+
+	if err := f.write(); err != nil {
+		f.jobs[name] = previous
+		return err
+	}
+
+	return nil
+}
+
+func (f *FileStore) write() error {
+	data, err := json.MarshalIndent(sortedDefinitions(f.jobs), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+
+	if err = tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), f.path)
+}
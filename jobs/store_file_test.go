@@ -0,0 +1,93 @@
+package jobs
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/metamogul/timing"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFileStore(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "jobs.json")
+
+	storeUnderTest, err := NewFileStore(path)
+	require.NoError(t, err)
+	require.NotNil(t, storeUnderTest)
+	require.Empty(t, storeUnderTest.jobs)
+	require.NoFileExists(t, path)
+}
+
+func TestNewFileStore_invalidFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "jobs.json")
+	require.NoError(t, os.WriteFile(path, []byte("not json"), 0o644))
+
+	_, err := NewFileStore(path)
+	require.Error(t, err)
+}
+
+func TestFileStore(t *testing.T) {
+	t.Parallel()
+
+	storeUnderTest, err := NewFileStore(filepath.Join(t.TempDir(), "jobs.json"))
+	require.NoError(t, err)
+
+	testJobStore(t, storeUnderTest)
+}
+
+func TestFileStore_reopen(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "jobs.json")
+
+	storeUnderTest, err := NewFileStore(path)
+	require.NoError(t, err)
+
+	require.NoError(t, storeUnderTest.SaveJob(timing.JobDefinition{
+		Name:          "report",
+		Action:        "sendReport",
+		Params:        json.RawMessage(`{"format":"pdf"}`),
+		Start:         now,
+		Interval:      time.Hour,
+		MisfirePolicy: timing.MisfireRunAll,
+	}))
+	require.NoError(t, storeUnderTest.SetLastRun("report", now.Add(time.Hour)))
+
+	reopenedStore, err := NewFileStore(path)
+	require.NoError(t, err)
+
+	definitions, err := reopenedStore.LoadJobs()
+	require.NoError(t, err)
+	require.Len(t, definitions, 1)
+	require.Equal(t, "report", definitions[0].Name)
+	require.Equal(t, "sendReport", definitions[0].Action)
+	require.JSONEq(t, `{"format":"pdf"}`, string(definitions[0].Params))
+	require.True(t, now.Equal(definitions[0].Start))
+	require.Equal(t, time.Hour, definitions[0].Interval)
+	require.Equal(t, timing.MisfireRunAll, definitions[0].MisfirePolicy)
+	require.True(t, now.Add(time.Hour).Equal(*definitions[0].LastRun))
+}
+
+func TestFileStore_writeFails(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	directory := filepath.Join(t.TempDir(), "missing")
+
+	storeUnderTest, err := NewFileStore(filepath.Join(directory, "jobs.json"))
+	require.NoError(t, err)
+
+	require.Error(t, storeUnderTest.SaveJob(timing.JobDefinition{Name: "report", Start: now}))
+
+	definitions, err := storeUnderTest.LoadJobs()
+	require.NoError(t, err)
+	require.Empty(t, definitions)
+}
@@ -0,0 +1,95 @@
+package jobs
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/metamogul/timing"
+)
+
+type MemoryStore struct {
+	mu   sync.RWMutex
+	jobs map[string]timing.JobDefinition
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		jobs: make(map[string]timing.JobDefinition),
+	}
+}
+
+func (m *MemoryStore) SaveJob(definition timing.JobDefinition) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.jobs[definition.Name] = copyDefinition(definition)
+
+	return nil
+}
+
+func (m *MemoryStore) DeleteJob(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, found := m.jobs[name]; !found {
+		return fmt.Errorf("%w: %s", timing.ErrJobNotFound, name)
+	}
+
+	delete(m.jobs, name)
+
+	return nil
+}
+
+func (m *MemoryStore) LoadJobs() ([]timing.JobDefinition, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return sortedDefinitions(m.jobs), nil
+}
+
+func (m *MemoryStore) SetLastRun(name string, lastRun time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	definition, found := m.jobs[name]
+	if !found {
+		return fmt.Errorf("%w: %s", timing.ErrJobNotFound, name)
+	}
+
+	definition.LastRun = &lastRun
+	m.jobs[name] = definition
+
+	return nil
+}
+
+func copyDefinition(definition timing.JobDefinition) timing.JobDefinition {
+	if definition.Until != nil {
+		until := *definition.Until
+		definition.Until = &until
+	}
+
+	if definition.LastRun != nil {
+		lastRun := *definition.LastRun
+		definition.LastRun = &lastRun
+	}
+
+	definition.Params = slices.Clone(definition.Params)
+
+	return definition
+}
+
+func sortedDefinitions(jobs map[string]timing.JobDefinition) []timing.JobDefinition {
+	definitions := make([]timing.JobDefinition, 0, len(jobs))
+	for _, definition := range jobs {
+		definitions = append(definitions, copyDefinition(definition))
+	}
+
+	slices.SortFunc(definitions, func(a, b timing.JobDefinition) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return definitions
+}
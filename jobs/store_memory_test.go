@@ -0,0 +1,60 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/metamogul/timing"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMemoryStore(t *testing.T) {
+	t.Parallel()
+
+	storeUnderTest := NewMemoryStore()
+	require.NotNil(t, storeUnderTest)
+	require.NotNil(t, storeUnderTest.jobs)
+}
+
+func TestMemoryStore(t *testing.T) {
+	t.Parallel()
+
+	testJobStore(t, NewMemoryStore())
+}
+
+func testJobStore(t *testing.T, store timing.JobStore) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	until := now.Add(24 * time.Hour)
+	report := timing.JobDefinition{Name: "report", Start: now, Interval: time.Hour, Until: &until}
+	cleanup := timing.JobDefinition{Name: "cleanup", Start: now, MisfirePolicy: timing.MisfireSkip}
+
+	require.NoError(t, store.SaveJob(report))
+	require.NoError(t, store.SaveJob(cleanup))
+
+	until = until.Add(time.Hour)
+
+	definitions, err := store.LoadJobs()
+	require.NoError(t, err)
+	require.Len(t, definitions, 2)
+	require.Equal(t, "cleanup", definitions[0].Name)
+	require.Equal(t, timing.MisfireSkip, definitions[0].MisfirePolicy)
+	require.Equal(t, "report", definitions[1].Name)
+	require.True(t, now.Add(24*time.Hour).Equal(*definitions[1].Until))
+	require.Nil(t, definitions[1].LastRun)
+
+	require.NoError(t, store.SetLastRun("report", now.Add(time.Hour)))
+	require.ErrorIs(t, store.SetLastRun("unknown", now), timing.ErrJobNotFound)
+
+	definitions, err = store.LoadJobs()
+	require.NoError(t, err)
+	require.True(t, now.Add(time.Hour).Equal(*definitions[1].LastRun))
+
+	require.NoError(t, store.DeleteJob("cleanup"))
+	require.ErrorIs(t, store.DeleteJob("cleanup"), timing.ErrJobNotFound)
+
+	definitions, err = store.LoadJobs()
+	require.NoError(t, err)
+	require.Len(t, definitions, 1)
+	require.Equal(t, "report", definitions[0].Name)
+}
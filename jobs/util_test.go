@@ -0,0 +1,5 @@
+package jobs
+
+func ptr[T any](t T) *T {
+	return &t
+}
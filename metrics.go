@@ -0,0 +1,21 @@
+package timing
+
+import (
+	"time"
+)
+
+// Metrics receives measurements from event schedulers. Every measurement is
+// labelled with the job name of the context the action was scheduled with,
+// see WithJobName; actions scheduled without one are reported under "".
+//
+// EventScheduled is reported once per call to a Perform method, even for
+// repeated actions. EventCancelled is reported when the context of a
+// scheduled action is cancelled while it still has occurrences left.
+// EventStarted and EventFinished bracket every single performance of an
+// action, so their difference is the number of actions in flight.
+type Metrics interface {
+	EventScheduled(job string)
+	EventCancelled(job string)
+	EventStarted(job string, lateness time.Duration)
+	EventFinished(job string, duration time.Duration)
+}
@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"expvar"
+	"sync"
+	"time"
+)
+
+// Expvar publishes scheduler metrics as an expvar.Map, keyed by metric and
+// then by job name.
+type Expvar struct {
+	scheduled *expvar.Map
+	cancelled *expvar.Map
+	executed  *expvar.Map
+	inFlight  *expvar.Map
+
+	histogramsMu sync.Mutex
+	duration     *expvar.Map
+	lateness     *expvar.Map
+}
+
+// NewExpvar publishes the metrics under name. Like expvar.Publish it panics
+// if name is already in use.
+func NewExpvar(name string) *Expvar {
+	e := &Expvar{
+		scheduled: new(expvar.Map),
+		cancelled: new(expvar.Map),
+		executed:  new(expvar.Map),
+		inFlight:  new(expvar.Map),
+		duration:  new(expvar.Map),
+		lateness:  new(expvar.Map),
+	}
+
+	root := expvar.NewMap(name)
+	root.Set("scheduled", e.scheduled)
+	root.Set("cancelled", e.cancelled)
+	root.Set("executed", e.executed)
+	root.Set("inFlight", e.inFlight)
+	root.Set("durationSeconds", e.duration)
+	root.Set("latenessSeconds", e.lateness)
+
+	return e
+}
+
+func (e *Expvar) EventScheduled(job string) {
+	e.scheduled.Add(job, 1)
+}
+
+func (e *Expvar) EventCancelled(job string) {
+	e.cancelled.Add(job, 1)
+}
+
+func (e *Expvar) EventStarted(job string, lateness time.Duration) {
+	e.inFlight.Add(job, 1)
+	e.histogram(e.lateness, job).observe(lateness)
+}
+
+func (e *Expvar) EventFinished(job string, duration time.Duration) {
+	e.inFlight.Add(job, -1)
+	e.executed.Add(job, 1)
+	e.histogram(e.duration, job).observe(duration)
+}
+
+func (e *Expvar) histogram(histograms *expvar.Map, job string) *histogram {
+	if h, ok := histograms.Get(job).(*histogram); ok {
+		return h
+	}
+
+	e.histogramsMu.Lock()
+	defer e.histogramsMu.Unlock()
+
+	if h, ok := histograms.Get(job).(*histogram); ok {
+		return h
+	}
+
+	h := newHistogram(DefaultBuckets)
+	histograms.Set(job, h)
+
+	return h
+}
@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var expvarRuns atomic.Int64
+
+// expvarName returns a name for t to publish under. expvar names can't be
+// reused, so it differs between runs, e.g. with -count.
+func expvarName(t *testing.T) string {
+	return fmt.Sprintf("%s-%d", t.Name(), expvarRuns.Add(1))
+}
+
+func TestExpvar(t *testing.T) {
+	t.Parallel()
+
+	name := expvarName(t)
+	e := NewExpvar(name)
+
+	e.EventScheduled("report")
+	e.EventScheduled("report")
+	e.EventCancelled("report")
+	e.EventStarted("report", 20*time.Millisecond)
+	e.EventFinished("report", 2*time.Second)
+	e.EventStarted("report", 0)
+
+	published := struct {
+		Scheduled       map[string]int64 `json:"scheduled"`
+		Cancelled       map[string]int64 `json:"cancelled"`
+		Executed        map[string]int64 `json:"executed"`
+		InFlight        map[string]int64 `json:"inFlight"`
+		DurationSeconds map[string]struct {
+			Count uint64  `json:"count"`
+			Sum   float64 `json:"sum"`
+		} `json:"durationSeconds"`
+		LatenessSeconds map[string]struct {
+			Count uint64 `json:"count"`
+		} `json:"latenessSeconds"`
+	}{}
+	require.NoError(t, json.Unmarshal([]byte(expvar.Get(name).String()), &published))
+
+	require.Equal(t, int64(2), published.Scheduled["report"])
+	require.Equal(t, int64(1), published.Cancelled["report"])
+	require.Equal(t, int64(1), published.Executed["report"])
+	require.Equal(t, int64(1), published.InFlight["report"])
+	require.Equal(t, uint64(1), published.DurationSeconds["report"].Count)
+	require.Equal(t, 2.0, published.DurationSeconds["report"].Sum)
+	require.Equal(t, uint64(2), published.LatenessSeconds["report"].Count)
+}
+
+func TestNewExpvar_nameInUse(t *testing.T) {
+	t.Parallel()
+
+	name := expvarName(t)
+	_ = NewExpvar(name)
+
+	require.Panics(t, func() {
+		_ = NewExpvar(name)
+	})
+}
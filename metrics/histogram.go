@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"encoding/json"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of the histograms for
+// action durations and dispatch lateness.
+var DefaultBuckets = []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5, 10, 60}
+
+type histogram struct {
+	mu sync.Mutex
+
+	bounds []float64
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+func newHistogram(bounds []float64) *histogram {
+	return &histogram{
+		bounds: slices.Clone(bounds),
+		counts: make([]uint64, len(bounds)),
+	}
+}
+
+func (h *histogram) observe(d time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	seconds := d.Seconds()
+
+	if i, _ := slices.BinarySearch(h.bounds, seconds); i < len(h.counts) {
+		h.counts[i]++
+	}
+
+	h.count++
+	h.sum += seconds
+}
+
+type histogramSnapshot struct {
+	bounds []float64
+	// cumulative holds the number of observations less than or equal to the
+	// corresponding bound.
+	cumulative []uint64
+	count      uint64
+	sum        float64
+}
+
+func (h *histogram) snapshot() histogramSnapshot {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	cumulative := make([]uint64, len(h.counts))
+
+	var total uint64
+	for i, count := range h.counts {
+		total += count
+		cumulative[i] = total
+	}
+
+	return histogramSnapshot{
+		bounds:     h.bounds,
+		cumulative: cumulative,
+		count:      h.count,
+		sum:        h.sum,
+	}
+}
+
+// String implements expvar.Var.
+func (h *histogram) String() string {
+	snapshot := h.snapshot()
+
+	buckets := make(map[string]uint64, len(snapshot.bounds))
+	for i, bound := range snapshot.bounds {
+		buckets[formatFloat(bound)] = snapshot.cumulative[i]
+	}
+
+	encoded, _ := json.Marshal(struct {
+		Count   uint64            `json:"count"`
+		Sum     float64           `json:"sum"`
+		Buckets map[string]uint64 `json:"buckets"`
+	}{snapshot.count, snapshot.sum, buckets})
+
+	return string(encoded)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,33 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_histogram_observe(t *testing.T) {
+	t.Parallel()
+
+	h := newHistogram([]float64{0.1, 1})
+
+	h.observe(50 * time.Millisecond)
+	h.observe(100 * time.Millisecond)
+	h.observe(500 * time.Millisecond)
+	h.observe(2 * time.Second)
+
+	snapshot := h.snapshot()
+	require.Equal(t, []uint64{2, 3}, snapshot.cumulative)
+	require.Equal(t, uint64(4), snapshot.count)
+	require.InDelta(t, 2.65, snapshot.sum, 1e-9)
+}
+
+func Test_histogram_String(t *testing.T) {
+	t.Parallel()
+
+	h := newHistogram([]float64{0.1, 1})
+	h.observe(500 * time.Millisecond)
+
+	require.JSONEq(t, `{"count":1,"sum":0.5,"buckets":{"0.1":0,"1":1}}`, h.String())
+}
@@ -0,0 +1,159 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+type jobMetrics struct {
+	scheduled uint64
+	cancelled uint64
+	executed  uint64
+	inFlight  int64
+
+	duration *histogram
+	lateness *histogram
+}
+
+// Registry collects scheduler metrics in memory and serves them in the
+// Prometheus text exposition format.
+type Registry struct {
+	mu   sync.Mutex
+	jobs map[string]*jobMetrics
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		jobs: make(map[string]*jobMetrics),
+	}
+}
+
+func (r *Registry) EventScheduled(job string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.job(job).scheduled++
+}
+
+func (r *Registry) EventCancelled(job string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.job(job).cancelled++
+}
+
+func (r *Registry) EventStarted(job string, lateness time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	metrics := r.job(job)
+	metrics.inFlight++
+	metrics.lateness.observe(lateness)
+}
+
+func (r *Registry) EventFinished(job string, duration time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	metrics := r.job(job)
+	metrics.inFlight--
+	metrics.executed++
+	metrics.duration.observe(duration)
+}
+
+func (r *Registry) job(name string) *jobMetrics {
+	metrics, ok := r.jobs[name]
+	if !ok {
+		metrics = &jobMetrics{
+			duration: newHistogram(DefaultBuckets),
+			lateness: newHistogram(DefaultBuckets),
+		}
+		r.jobs[name] = metrics
+	}
+
+	return metrics
+}
+
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = r.WritePrometheus(w)
+}
+
+// WritePrometheus writes all metrics in the Prometheus text exposition format.
+// Jobs are labelled job_name, since Prometheus sets job to the scrape target.
+func (r *Registry) WritePrometheus(w io.Writer) error {
+	r.mu.Lock()
+
+	names := make([]string, 0, len(r.jobs))
+	for name := range r.jobs {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	jobs := make([]jobMetrics, len(names))
+	for i, name := range names {
+		jobs[i] = *r.jobs[name]
+	}
+
+	r.mu.Unlock()
+
+	buffered := bufio.NewWriter(w)
+
+	counters := []struct {
+		name, help string
+		value      func(jobMetrics) string
+	}{
+		{"timing_events_scheduled_total", "Number of actions scheduled.", func(j jobMetrics) string { return fmt.Sprint(j.scheduled) }},
+		{"timing_events_executed_total", "Number of action performances that finished.", func(j jobMetrics) string { return fmt.Sprint(j.executed) }},
+		{"timing_events_cancelled_total", "Number of scheduled actions cancelled before their last performance.", func(j jobMetrics) string { return fmt.Sprint(j.cancelled) }},
+	}
+
+	for _, counter := range counters {
+		writeHeader(buffered, counter.name, counter.help, "counter")
+		for i, name := range names {
+			fmt.Fprintf(buffered, "%s{job_name=%s} %s\n", counter.name, quote(name), counter.value(jobs[i]))
+		}
+	}
+
+	writeHeader(buffered, "timing_events_in_flight", "Number of actions currently being performed.", "gauge")
+	for i, name := range names {
+		fmt.Fprintf(buffered, "timing_events_in_flight{job_name=%s} %d\n", quote(name), jobs[i].inFlight)
+	}
+
+	writeHistogram(buffered, "timing_action_duration_seconds", "Duration of action performances.", names, jobs, func(j jobMetrics) *histogram { return j.duration })
+	writeHistogram(buffered, "timing_dispatch_lateness_seconds", "Time between the scheduled and the actual start of action performances.", names, jobs, func(j jobMetrics) *histogram { return j.lateness })
+
+	return buffered.Flush()
+}
+
+func writeHeader(w io.Writer, name, help, kind string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func writeHistogram(w io.Writer, name, help string, names []string, jobs []jobMetrics, histogramOf func(jobMetrics) *histogram) {
+	writeHeader(w, name, help, "histogram")
+
+	for i, job := range names {
+		snapshot := histogramOf(jobs[i]).snapshot()
+		label := quote(job)
+
+		for j, bound := range snapshot.bounds {
+			fmt.Fprintf(w, "%s_bucket{job_name=%s,le=\"%s\"} %d\n", name, label, formatFloat(bound), snapshot.cumulative[j])
+		}
+		fmt.Fprintf(w, "%s_bucket{job_name=%s,le=\"+Inf\"} %d\n", name, label, snapshot.count)
+		fmt.Fprintf(w, "%s_sum{job_name=%s} %s\n", name, label, formatFloat(snapshot.sum))
+		fmt.Fprintf(w, "%s_count{job_name=%s} %d\n", name, label, snapshot.count)
+	}
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func quote(labelValue string) string {
+	return `"` + labelValueReplacer.Replace(labelValue) + `"`
+}
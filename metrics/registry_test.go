@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+
+	r.EventScheduled("report")
+	r.EventStarted("report", 20*time.Millisecond)
+	r.EventFinished("report", 300*time.Millisecond)
+	r.EventScheduled("cleanup")
+	r.EventCancelled("cleanup")
+	r.EventStarted("cleanup", 0)
+
+	recorder := httptest.NewRecorder()
+	r.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "text/plain; version=0.0.4; charset=utf-8", recorder.Header().Get("Content-Type"))
+
+	lines := strings.Split(recorder.Body.String(), "\n")
+	for _, line := range []string{
+		"# TYPE timing_events_scheduled_total counter",
+		`timing_events_scheduled_total{job_name="cleanup"} 1`,
+		`timing_events_scheduled_total{job_name="report"} 1`,
+		`timing_events_executed_total{job_name="cleanup"} 0`,
+		`timing_events_executed_total{job_name="report"} 1`,
+		`timing_events_cancelled_total{job_name="cleanup"} 1`,
+		"# TYPE timing_events_in_flight gauge",
+		`timing_events_in_flight{job_name="cleanup"} 1`,
+		`timing_events_in_flight{job_name="report"} 0`,
+		"# TYPE timing_action_duration_seconds histogram",
+		`timing_action_duration_seconds_bucket{job_name="report",le="0.1"} 0`,
+		`timing_action_duration_seconds_bucket{job_name="report",le="0.5"} 1`,
+		`timing_action_duration_seconds_bucket{job_name="report",le="+Inf"} 1`,
+		`timing_action_duration_seconds_sum{job_name="report"} 0.3`,
+		`timing_action_duration_seconds_count{job_name="report"} 1`,
+		`timing_dispatch_lateness_seconds_bucket{job_name="cleanup",le="0.001"} 1`,
+		`timing_dispatch_lateness_seconds_count{job_name="report"} 1`,
+	} {
+		require.Contains(t, lines, line)
+	}
+
+	require.Less(t, strings.Index(recorder.Body.String(), `{job_name="cleanup"}`), strings.Index(recorder.Body.String(), `{job_name="report"}`))
+}
+
+func Test_quote(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, `""`, quote(""))
+	require.Equal(t, `"a\\b\"c\nd"`, quote("a\\b\"c\nd"))
+}
@@ -0,0 +1,102 @@
+package timing
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+type ActionFunc func(ActionContext)
+
+func (f ActionFunc) Perform(ctx ActionContext) { f(ctx) }
+
+// ActionMiddleware wraps an action, e.g. to log or guard its performances.
+type ActionMiddleware func(Action) Action
+
+// Chain wraps action in middlewares, the first of which ends up outermost.
+func Chain(action Action, middlewares ...ActionMiddleware) Action {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		action = middlewares[i](action)
+	}
+
+	return action
+}
+
+type middlewareKey struct{}
+
+// WithMiddleware installs middlewares for every action scheduled with the
+// returned context. Schedulers apply them inside their own middlewares and
+// inside those already installed in ctx.
+func WithMiddleware(ctx context.Context, middlewares ...ActionMiddleware) context.Context {
+	return context.WithValue(ctx, middlewareKey{}, slices.Concat(MiddlewareFrom(ctx), middlewares))
+}
+
+func MiddlewareFrom(ctx context.Context) []ActionMiddleware {
+	middlewares, _ := ctx.Value(middlewareKey{}).([]ActionMiddleware)
+	return middlewares
+}
+
+type schedulerKey struct{}
+
+// WithScheduler makes actions scheduled with the returned context see
+// scheduler as their ActionContext's Scheduler, so that schedulers wrapping
+// another one get the follow-up work scheduled through them. It replaces a
+// scheduler already installed in ctx, so wrapping schedulers only install
+// themselves if SchedulerFrom(ctx) is nil, and the outermost one is seen.
+func WithScheduler(ctx context.Context, scheduler EventScheduler) context.Context {
+	return context.WithValue(ctx, schedulerKey{}, scheduler)
+}
+
+// SchedulerFrom returns the scheduler installed with WithScheduler, or nil.
+func SchedulerFrom(ctx context.Context) EventScheduler {
+	scheduler, _ := ctx.Value(schedulerKey{}).(EventScheduler)
+	return scheduler
+}
+
+type derivedActionContext struct {
+	context.Context
+	parent ActionContext
+}
+
+// DeriveActionContext returns an ActionContext that uses ctx, which should be
+// derived from parent, as its context and defers everything else to parent.
+// Middlewares use it to hand a cancellable or annotated context to the
+// actions they wrap.
+func DeriveActionContext(parent ActionContext, ctx context.Context) ActionContext {
+	return &derivedActionContext{
+		Context: ctx,
+		parent:  parent,
+	}
+}
+
+func (d *derivedActionContext) Clock() Clock {
+	return d.parent.Clock()
+}
+
+func (d *derivedActionContext) DoneSchedulingNewEvents() {
+	d.parent.DoneSchedulingNewEvents()
+}
+
+func (d *derivedActionContext) Metadata() EventMetadata {
+	return d.parent.Metadata()
+}
+
+func (d *derivedActionContext) ScheduledTime() time.Time {
+	return d.parent.ScheduledTime()
+}
+
+func (d *derivedActionContext) EventID() uint64 {
+	return d.parent.EventID()
+}
+
+func (d *derivedActionContext) JobName() string {
+	return d.parent.JobName()
+}
+
+func (d *derivedActionContext) Occurrence() int {
+	return d.parent.Occurrence()
+}
+
+func (d *derivedActionContext) Scheduler() EventScheduler {
+	return d.parent.Scheduler()
+}
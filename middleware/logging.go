@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"log/slog"
+
+	"github.com/metamogul/timing"
+)
+
+// Logging logs the start and the end of every performance at info level,
+// together with the job name, the scheduled time and, at the end, how long
+// the action took on the action's clock. A nil logger means slog.Default.
+func Logging(logger *slog.Logger) timing.ActionMiddleware {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return func(action timing.Action) timing.Action {
+		return timing.ActionFunc(func(ctx timing.ActionContext) {
+			metadata := ctx.Metadata()
+			attrs := []any{
+				slog.String("job", metadata.JobName),
+				slog.Time("scheduledTime", metadata.ScheduledTime),
+			}
+
+			startedAt := ctx.Clock().Now()
+			logger.InfoContext(ctx, "performing action", attrs...)
+
+			action.Perform(ctx)
+
+			logger.InfoContext(ctx, "performed action", append(attrs, slog.Duration("duration", ctx.Clock().Now().Sub(startedAt)))...)
+		})
+	}
+}
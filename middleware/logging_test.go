@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/metamogul/timing"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogging(t *testing.T) {
+	t.Parallel()
+
+	buffer := &bytes.Buffer{}
+	logger := slog.New(slog.NewTextHandler(buffer, nil))
+
+	performed := false
+	action := Logging(logger)(timing.ActionFunc(func(timing.ActionContext) { performed = true }))
+
+	action.Perform(newTestActionContext("report"))
+	require.True(t, performed)
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	require.Len(t, lines, 2)
+	require.Contains(t, lines[0], `msg="performing action" job=report scheduledTime=2024-01-01T12:00:00.000Z`)
+	require.Contains(t, lines[1], `msg="performed action" job=report scheduledTime=2024-01-01T12:00:00.000Z duration=0s`)
+}
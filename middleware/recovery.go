@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"log/slog"
+
+	"github.com/metamogul/timing"
+)
+
+// Recovery recovers panics of the actions it wraps and passes them to
+// handler. A nil handler logs them with slog.Default.
+func Recovery(handler func(ctx timing.ActionContext, recovered any)) timing.ActionMiddleware {
+	if handler == nil {
+		handler = func(ctx timing.ActionContext, recovered any) {
+			slog.Default().ErrorContext(ctx, "action panicked", slog.String("job", ctx.Metadata().JobName), slog.Any("panic", recovered))
+		}
+	}
+
+	return func(action timing.Action) timing.Action {
+		return timing.ActionFunc(func(ctx timing.ActionContext) {
+			defer func() {
+				if recovered := recover(); recovered != nil {
+					handler(ctx, recovered)
+				}
+			}()
+
+			action.Perform(ctx)
+		})
+	}
+}
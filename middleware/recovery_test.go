@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"testing"
+
+	"github.com/metamogul/timing"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecovery(t *testing.T) {
+	t.Parallel()
+
+	t.Run("panic", func(t *testing.T) {
+		t.Parallel()
+
+		var recovered any
+		action := Recovery(func(_ timing.ActionContext, r any) { recovered = r })(
+			timing.ActionFunc(func(timing.ActionContext) { panic("boom") }),
+		)
+
+		require.NotPanics(t, func() { action.Perform(newTestActionContext("report")) })
+		require.Equal(t, "boom", recovered)
+	})
+
+	t.Run("no panic", func(t *testing.T) {
+		t.Parallel()
+
+		action := Recovery(func(timing.ActionContext, any) { t.Error("handler called") })(
+			timing.ActionFunc(func(timing.ActionContext) {}),
+		)
+
+		action.Perform(newTestActionContext("report"))
+	})
+
+	t.Run("default handler", func(t *testing.T) {
+		t.Parallel()
+
+		action := Recovery(nil)(timing.ActionFunc(func(timing.ActionContext) { panic("boom") }))
+
+		require.NotPanics(t, func() { action.Perform(newTestActionContext("report")) })
+	})
+}
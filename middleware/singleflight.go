@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"sync"
+
+	"github.com/metamogul/timing"
+)
+
+// Singleflight skips a performance while the previous performance of the
+// same job is still running. Actions without a job name are always
+// performed, as nothing tells them apart.
+func Singleflight() timing.ActionMiddleware {
+	mu := sync.Mutex{}
+	running := make(map[string]bool)
+
+	return func(action timing.Action) timing.Action {
+		return timing.ActionFunc(func(ctx timing.ActionContext) {
+			job := ctx.JobName()
+			if job == "" {
+				action.Perform(ctx)
+				return
+			}
+
+			mu.Lock()
+			if running[job] {
+				mu.Unlock()
+
+				// A skipped action must not keep an AsyncEventScheduler
+				// waiting for it to schedule new events.
+				ctx.DoneSchedulingNewEvents()
+				return
+			}
+			running[job] = true
+			mu.Unlock()
+
+			defer func() {
+				mu.Lock()
+				delete(running, job)
+				mu.Unlock()
+			}()
+
+			action.Perform(ctx)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/metamogul/timing"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSingleflight(t *testing.T) {
+	t.Parallel()
+
+	middleware := Singleflight()
+
+	performed := atomic.Int32{}
+	inner := middleware(timing.ActionFunc(func(timing.ActionContext) { performed.Add(1) }))
+
+	skipped := newTestActionContext("report")
+	outer := middleware(timing.ActionFunc(func(ctx timing.ActionContext) {
+		performed.Add(1)
+
+		// Overlaps with the running performance of the same job.
+		inner.Perform(skipped)
+		// Other jobs are not affected.
+		inner.Perform(newTestActionContext("cleanup"))
+	}))
+
+	outer.Perform(newTestActionContext("report"))
+	require.Equal(t, int32(2), performed.Load())
+	require.Equal(t, 1, skipped.doneSchedulingNewEvent)
+
+	// Once finished, the job can run again.
+	inner.Perform(newTestActionContext("report"))
+	require.Equal(t, int32(3), performed.Load())
+}
+
+func TestSingleflight_unnamed(t *testing.T) {
+	t.Parallel()
+
+	middleware := Singleflight()
+
+	performed := atomic.Int32{}
+	inner := middleware(timing.ActionFunc(func(timing.ActionContext) { performed.Add(1) }))
+
+	outer := middleware(timing.ActionFunc(func(timing.ActionContext) {
+		performed.Add(1)
+		inner.Perform(newTestActionContext(""))
+	}))
+
+	outer.Perform(newTestActionContext(""))
+	require.Equal(t, int32(2), performed.Load())
+}
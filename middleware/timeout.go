@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/metamogul/timing"
+)
+
+// Timeout hands every performance a context that is cancelled once timeout
+// has passed, measured on the performing scheduler's clock if it is a
+// timing.DeadlineScheduler and in real time otherwise. Actions have to watch
+// the context to stop early.
+func Timeout(timeout time.Duration) timing.ActionMiddleware {
+	if timeout <= 0 {
+		panic("timeout must be greater than zero")
+	}
+
+	return func(action timing.Action) timing.Action {
+		return timing.ActionFunc(func(ctx timing.ActionContext) {
+			withTimeout := context.WithTimeout
+			if scheduler, ok := ctx.Scheduler().(timing.DeadlineScheduler); ok {
+				withTimeout = scheduler.WithTimeout
+			}
+
+			timeoutCtx, cancel := withTimeout(ctx, timeout)
+			defer cancel()
+
+			action.Perform(timing.DeriveActionContext(ctx, timeoutCtx))
+		})
+	}
+}
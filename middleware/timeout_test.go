@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/metamogul/timing"
+	"github.com/metamogul/timing/simulated_time"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeout(t *testing.T) {
+	t.Parallel()
+
+	require.Panics(t, func() {
+		_ = Timeout(0)
+	})
+
+	parent := newTestActionContext("report")
+
+	var actionCtx timing.ActionContext
+	action := Timeout(10 * time.Millisecond)(timing.ActionFunc(func(ctx timing.ActionContext) {
+		actionCtx = ctx
+
+		<-ctx.Done()
+		require.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+	}))
+
+	action.Perform(parent)
+
+	require.Equal(t, parent.Metadata(), actionCtx.Metadata())
+	require.Equal(t, parent.Clock(), actionCtx.Clock())
+	require.NoError(t, parent.Err())
+}
+
+func TestTimeout_simulatedTime(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	scheduler := simulated_time.NewSerialEventScheduler(now)
+	scheduler.Middleware = []timing.ActionMiddleware{Timeout(time.Hour)}
+
+	var deadline time.Time
+	scheduler.PerformAfter(timing.ActionFunc(func(ctx timing.ActionContext) {
+		deadline, _ = ctx.Deadline()
+	}), time.Minute, context.Background())
+
+	scheduler.Forward(time.Minute)
+
+	require.Equal(t, now.Add(time.Minute+time.Hour), deadline)
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/metamogul/timing"
+)
+
+type Span interface {
+	End()
+}
+
+// Tracer starts spans. It is small enough to be implemented by an adapter to
+// any tracing library.
+type Tracer interface {
+	Start(ctx context.Context, spanName string) (context.Context, Span)
+}
+
+// Tracing performs every action inside a span named after its job, or
+// "timing.Action" for actions without a job name.
+func Tracing(tracer Tracer) timing.ActionMiddleware {
+	if tracer == nil {
+		panic("tracer can't be nil")
+	}
+
+	return func(action timing.Action) timing.Action {
+		return timing.ActionFunc(func(ctx timing.ActionContext) {
+			spanName := ctx.Metadata().JobName
+			if spanName == "" {
+				spanName = "timing.Action"
+			}
+
+			spanCtx, span := tracer.Start(ctx, spanName)
+			defer span.End()
+
+			action.Perform(timing.DeriveActionContext(ctx, spanCtx))
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+
+	"github.com/metamogul/timing"
+	"github.com/stretchr/testify/require"
+)
+
+type spanKey struct{}
+
+type recordingSpan struct {
+	name  string
+	ended bool
+}
+
+func (r *recordingSpan) End() { r.ended = true }
+
+type recordingTracer struct {
+	spans []*recordingSpan
+}
+
+func (r *recordingTracer) Start(ctx context.Context, spanName string) (context.Context, Span) {
+	span := &recordingSpan{name: spanName}
+	r.spans = append(r.spans, span)
+
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+func TestTracing(t *testing.T) {
+	t.Parallel()
+
+	require.Panics(t, func() {
+		_ = Tracing(nil)
+	})
+
+	tracer := &recordingTracer{}
+	middleware := Tracing(tracer)
+
+	action := middleware(timing.ActionFunc(func(ctx timing.ActionContext) {
+		span := ctx.Value(spanKey{}).(*recordingSpan)
+		require.False(t, span.ended)
+	}))
+
+	action.Perform(newTestActionContext("report"))
+	action.Perform(newTestActionContext(""))
+
+	require.Len(t, tracer.spans, 2)
+	require.Equal(t, "report", tracer.spans[0].name)
+	require.Equal(t, "timing.Action", tracer.spans[1].name)
+	require.True(t, tracer.spans[0].ended)
+	require.True(t, tracer.spans[1].ended)
+}
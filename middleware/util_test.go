@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/metamogul/timing"
+)
+
+type fixedClock time.Time
+
+func (f fixedClock) Now() time.Time { return time.Time(f) }
+
+type testActionContext struct {
+	context.Context
+
+	metadata               timing.EventMetadata
+	doneSchedulingNewEvent int
+}
+
+func newTestActionContext(job string) *testActionContext {
+	return &testActionContext{
+		Context: context.Background(),
+		metadata: timing.EventMetadata{
+			ScheduledTime: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+			JobName:       job,
+		},
+	}
+}
+
+func (t *testActionContext) Clock() timing.Clock {
+	return fixedClock(t.metadata.ScheduledTime)
+}
+
+func (t *testActionContext) DoneSchedulingNewEvents() {
+	t.doneSchedulingNewEvent++
+}
+
+func (t *testActionContext) Metadata() timing.EventMetadata {
+	return t.metadata
+}
+
+func (t *testActionContext) ScheduledTime() time.Time {
+	return t.metadata.ScheduledTime
+}
+
+func (t *testActionContext) EventID() uint64 {
+	return t.metadata.EventID
+}
+
+func (t *testActionContext) JobName() string {
+	return t.metadata.JobName
+}
+
+func (t *testActionContext) Occurrence() int {
+	return t.metadata.Occurrence
+}
+
+func (t *testActionContext) Scheduler() timing.EventScheduler {
+	return nil
+}
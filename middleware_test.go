@@ -0,0 +1,111 @@
+package timing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func recordingMiddleware(name string, calls *[]string) ActionMiddleware {
+	return func(action Action) Action {
+		return ActionFunc(func(ctx ActionContext) {
+			*calls = append(*calls, name)
+			action.Perform(ctx)
+		})
+	}
+}
+
+func TestChain(t *testing.T) {
+	t.Parallel()
+
+	calls := make([]string, 0)
+
+	action := Chain(
+		ActionFunc(func(ActionContext) { calls = append(calls, "action") }),
+		recordingMiddleware("outer", &calls),
+		recordingMiddleware("inner", &calls),
+	)
+	action.Perform(nil)
+
+	require.Equal(t, []string{"outer", "inner", "action"}, calls)
+}
+
+func TestWithMiddleware(t *testing.T) {
+	t.Parallel()
+
+	calls := make([]string, 0)
+
+	require.Empty(t, MiddlewareFrom(context.Background()))
+
+	ctx := WithMiddleware(context.Background(), recordingMiddleware("first", &calls))
+	ctx = WithMiddleware(ctx, recordingMiddleware("second", &calls))
+
+	middlewares := MiddlewareFrom(ctx)
+	require.Len(t, middlewares, 2)
+
+	Chain(ActionFunc(func(ActionContext) {}), middlewares...).Perform(nil)
+	require.Equal(t, []string{"first", "second"}, calls)
+}
+
+type testActionContext struct {
+	context.Context
+	scheduler               EventScheduler
+	doneSchedulingNewEvents bool
+}
+
+func (t *testActionContext) Clock() Clock { return nil }
+
+func (t *testActionContext) DoneSchedulingNewEvents() { t.doneSchedulingNewEvents = true }
+
+func (t *testActionContext) Metadata() EventMetadata {
+	return EventMetadata{ScheduledTime: time.Time{}.Add(time.Hour), JobName: "job", EventID: 3, Occurrence: 2}
+}
+
+func (t *testActionContext) ScheduledTime() time.Time { return t.Metadata().ScheduledTime }
+
+func (t *testActionContext) EventID() uint64 { return t.Metadata().EventID }
+
+func (t *testActionContext) JobName() string { return t.Metadata().JobName }
+
+func (t *testActionContext) Occurrence() int { return t.Metadata().Occurrence }
+
+func (t *testActionContext) Scheduler() EventScheduler { return t.scheduler }
+
+func TestDeriveActionContext(t *testing.T) {
+	t.Parallel()
+
+	parent := &testActionContext{Context: context.Background(), scheduler: &pendingEventScheduler{}}
+	ctx, cancel := context.WithCancel(parent)
+	cancel()
+
+	derived := DeriveActionContext(parent, ctx)
+
+	require.ErrorIs(t, derived.Err(), context.Canceled)
+	require.NoError(t, parent.Err())
+	require.Equal(t, parent.Metadata(), derived.Metadata())
+	require.Equal(t, parent.ScheduledTime(), derived.ScheduledTime())
+	require.Equal(t, uint64(3), derived.EventID())
+	require.Equal(t, "job", derived.JobName())
+	require.Equal(t, 2, derived.Occurrence())
+	require.Same(t, parent.scheduler, derived.Scheduler())
+
+	derived.DoneSchedulingNewEvents()
+	require.True(t, parent.doneSchedulingNewEvents)
+}
+
+func TestWithScheduler(t *testing.T) {
+	t.Parallel()
+
+	first, second := &pendingEventScheduler{}, &pendingEventScheduler{}
+
+	require.Nil(t, SchedulerFrom(context.Background()))
+
+	ctx := WithScheduler(context.Background(), first)
+	require.Same(t, first, SchedulerFrom(ctx))
+
+	ctx = WithScheduler(ctx, second)
+	require.Same(t, second, SchedulerFrom(ctx))
+	require.Empty(t, MiddlewareFrom(ctx))
+}
@@ -9,12 +9,33 @@ type Clock interface {
 	Now() time.Time
 }
 
-const ActionContextClockKey = "actionContextClock"
+// EventMetadata describes the event an action is performed for. EventID is
+// unique among the events performed by one scheduler and zero for events not
+// performed yet. Occurrence counts the events of a repeated action from 1,
+// and is zero where that isn't known.
+type EventMetadata struct {
+	ScheduledTime time.Time
+	JobName       string
+	EventID       uint64
+	Occurrence    int
+}
 
 type ActionContext interface {
 	context.Context
 	Clock() Clock
 	DoneSchedulingNewEvents()
+	Metadata() EventMetadata
+
+	// ScheduledTime, EventID, JobName and Occurrence return the respective
+	// fields of Metadata.
+	ScheduledTime() time.Time
+	EventID() uint64
+	JobName() string
+	Occurrence() int
+
+	// Scheduler returns the scheduler performing the action, so that it can
+	// schedule follow-up work.
+	Scheduler() EventScheduler
 }
 
 type Action interface {
@@ -0,0 +1,147 @@
+package timing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+var ErrActionNotRegistered = errors.New("action not registered")
+
+// ActionFactory creates an action from serialized parameters.
+type ActionFactory func(params json.RawMessage) (Action, error)
+
+// ActionRegistry maps action names to factories, so that actions can be
+// described by a name and serialized parameters.
+type ActionRegistry struct {
+	mu        sync.RWMutex
+	factories map[string]ActionFactory
+}
+
+func NewActionRegistry() *ActionRegistry {
+	return &ActionRegistry{
+		factories: make(map[string]ActionFactory),
+	}
+}
+
+var DefaultActionRegistry = NewActionRegistry()
+
+func (r *ActionRegistry) Register(name string, factory ActionFactory) {
+	if name == "" {
+		panic("name can't be empty")
+	}
+
+	if factory == nil {
+		panic("factory can't be nil")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.factories[name]; exists {
+		panic("action " + name + " is already registered")
+	}
+
+	r.factories[name] = factory
+}
+
+func (r *ActionRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.factories))
+	for name := range r.factories {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// New creates the action registered as name from params. The factory and the
+// returned NamedAction each get their own copy of params.
+func (r *ActionRegistry) New(name string, params json.RawMessage) (*NamedAction, error) {
+	r.mu.RLock()
+	factory, found := r.factories[name]
+	r.mu.RUnlock()
+
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrActionNotRegistered, name)
+	}
+
+	action, err := factory(slices.Clone(params))
+	if err != nil {
+		return nil, fmt.Errorf("creating action %s: %w", name, err)
+	}
+
+	if action == nil {
+		return nil, fmt.Errorf("creating action %s: factory returned nil", name)
+	}
+
+	return &NamedAction{
+		Name:   name,
+		Params: slices.Clone(params),
+		Action: action,
+	}, nil
+}
+
+// NamedAction is an action that remembers the name and parameters it was
+// created from.
+type NamedAction struct {
+	Name   string
+	Params json.RawMessage
+	Action
+}
+
+// NamedEventScheduler lets any EventScheduler perform actions given by name
+// and parameters. It resolves them through Registry, or through
+// DefaultActionRegistry if Registry is nil.
+type NamedEventScheduler struct {
+	EventScheduler
+	Registry *ActionRegistry
+}
+
+func (n NamedEventScheduler) PerformNamedNow(name string, params json.RawMessage, ctx context.Context) error {
+	action, err := n.registry().New(name, params)
+	if err != nil {
+		return err
+	}
+
+	n.PerformNow(action, ctx)
+
+	return nil
+}
+
+func (n NamedEventScheduler) PerformNamedAfter(name string, params json.RawMessage, duration time.Duration, ctx context.Context) error {
+	action, err := n.registry().New(name, params)
+	if err != nil {
+		return err
+	}
+
+	n.PerformAfter(action, duration, ctx)
+
+	return nil
+}
+
+func (n NamedEventScheduler) PerformNamedRepeatedly(name string, params json.RawMessage, until *time.Time, interval time.Duration, ctx context.Context) error {
+	action, err := n.registry().New(name, params)
+	if err != nil {
+		return err
+	}
+
+	n.PerformRepeatedly(action, until, interval, ctx)
+
+	return nil
+}
+
+func (n NamedEventScheduler) registry() *ActionRegistry {
+	if n.Registry == nil {
+		return DefaultActionRegistry
+	}
+
+	return n.Registry
+}
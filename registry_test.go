@@ -0,0 +1,115 @@
+package timing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewActionRegistry(t *testing.T) {
+	t.Parallel()
+
+	registryUnderTest := NewActionRegistry()
+	require.NotNil(t, registryUnderTest)
+	require.NotNil(t, registryUnderTest.factories)
+	require.Empty(t, registryUnderTest.Names())
+}
+
+func TestActionRegistry_Register(t *testing.T) {
+	t.Parallel()
+
+	factory := func(json.RawMessage) (Action, error) { return NewMockAction(t), nil }
+
+	registryUnderTest := NewActionRegistry()
+
+	require.Panics(t, func() { registryUnderTest.Register("", factory) })
+	require.Panics(t, func() { registryUnderTest.Register("report", nil) })
+
+	registryUnderTest.Register("report", factory)
+	registryUnderTest.Register("cleanup", factory)
+	require.Panics(t, func() { registryUnderTest.Register("report", factory) })
+
+	require.Equal(t, []string{"cleanup", "report"}, registryUnderTest.Names())
+}
+
+func TestActionRegistry_New(t *testing.T) {
+	t.Parallel()
+
+	registryUnderTest := NewActionRegistry()
+
+	var gotParams json.RawMessage
+	mockAction := NewMockAction(t)
+	registryUnderTest.Register("report", func(params json.RawMessage) (Action, error) {
+		gotParams = params
+		return mockAction, nil
+	})
+	registryUnderTest.Register("failing", func(json.RawMessage) (Action, error) {
+		return nil, errors.New("invalid params")
+	})
+	registryUnderTest.Register("nil", func(json.RawMessage) (Action, error) {
+		return nil, nil
+	})
+
+	params := json.RawMessage(`{"format":"pdf"}`)
+
+	namedAction, err := registryUnderTest.New("report", params)
+	require.NoError(t, err)
+	require.Equal(t, "report", namedAction.Name)
+	require.Equal(t, params, namedAction.Params)
+	require.Equal(t, mockAction, namedAction.Action)
+	require.Equal(t, params, gotParams)
+
+	params[2] = 'F'
+	gotParams[3] = 'O'
+	require.Equal(t, json.RawMessage(`{"format":"pdf"}`), namedAction.Params)
+
+	_, err = registryUnderTest.New("unknown", nil)
+	require.ErrorIs(t, err, ErrActionNotRegistered)
+
+	_, err = registryUnderTest.New("failing", nil)
+	require.ErrorContains(t, err, "invalid params")
+
+	_, err = registryUnderTest.New("nil", nil)
+	require.Error(t, err)
+}
+
+func TestNamedEventScheduler(t *testing.T) {
+	t.Parallel()
+
+	registry := NewActionRegistry()
+
+	mockAction := NewMockAction(t)
+	mockAction.EXPECT().
+		Perform(mock.Anything).
+		Times(3)
+
+	registry.Register("report", func(json.RawMessage) (Action, error) { return mockAction, nil })
+
+	scheduler := &immediateEventScheduler{}
+	schedulerUnderTest := NamedEventScheduler{EventScheduler: scheduler, Registry: registry}
+
+	require.NoError(t, schedulerUnderTest.PerformNamedNow("report", nil, context.Background()))
+	require.NoError(t, schedulerUnderTest.PerformNamedAfter("report", nil, time.Second, context.Background()))
+	require.NoError(t, schedulerUnderTest.PerformNamedRepeatedly("report", nil, nil, time.Second, context.Background()))
+
+	require.ErrorIs(t, schedulerUnderTest.PerformNamedNow("unknown", nil, context.Background()), ErrActionNotRegistered)
+	require.ErrorIs(t, schedulerUnderTest.PerformNamedAfter("unknown", nil, time.Second, context.Background()), ErrActionNotRegistered)
+	require.ErrorIs(t, schedulerUnderTest.PerformNamedRepeatedly("unknown", nil, nil, time.Second, context.Background()), ErrActionNotRegistered)
+
+	require.Len(t, scheduler.performed, 3)
+	for _, action := range scheduler.performed {
+		require.IsType(t, &NamedAction{}, action)
+		require.Equal(t, "report", action.(*NamedAction).Name)
+	}
+}
+
+func TestNamedEventScheduler_defaultRegistry(t *testing.T) {
+	t.Parallel()
+
+	require.Same(t, DefaultActionRegistry, NamedEventScheduler{}.registry())
+}
@@ -4,23 +4,49 @@ import (
 	"context"
 	"github.com/metamogul/timing"
 	"sync"
+	"time"
 )
 
+// ActionContextEventLoopBlockerKey is answered with their event loop blocker
+// by the action contexts of SchedulingActions.
+//
+// Deprecated: Use EventLoopBlockerFrom.
 const ActionContextEventLoopBlockerKey = "actionContextEventLoopBlocker"
 
+type eventLoopBlockerKey struct{}
+
+// EventLoopBlockerFrom returns the wait group that blocks the event loop
+// until the SchedulingAction performed with ctx is done scheduling new
+// events, or nil for other actions.
+func EventLoopBlockerFrom(ctx context.Context) *sync.WaitGroup {
+	eventLoopBlocker, _ := ctx.Value(eventLoopBlockerKey{}).(*sync.WaitGroup)
+	return eventLoopBlocker
+}
+
 type actionContext struct {
 	context.Context
+	// values is Context carrying clock, for ClockFrom.
+	values context.Context
 
 	clock            timing.Clock
 	eventLoopBlocker *sync.WaitGroup
+	metadata         timing.EventMetadata
+	scheduler        timing.EventScheduler
 }
 
-func newActionContext(ctx context.Context, clock timing.Clock, eventLoopBlocker *sync.WaitGroup) *actionContext {
+func newActionContext(ctx context.Context, clock timing.Clock, eventLoopBlocker *sync.WaitGroup, metadata timing.EventMetadata, scheduler timing.EventScheduler) *actionContext {
+	if override := timing.SchedulerFrom(ctx); override != nil {
+		scheduler = override
+	}
+
 	return &actionContext{
 		Context: ctx,
+		values:  timing.WithClock(ctx, clock),
 
 		clock:            clock,
 		eventLoopBlocker: eventLoopBlocker,
+		metadata:         metadata,
+		scheduler:        scheduler,
 	}
 }
 
@@ -36,13 +62,37 @@ func (a *actionContext) DoneSchedulingNewEvents() {
 	a.eventLoopBlocker.Done()
 }
 
+func (a *actionContext) Metadata() timing.EventMetadata {
+	return a.metadata
+}
+
+func (a *actionContext) ScheduledTime() time.Time {
+	return a.metadata.ScheduledTime
+}
+
+func (a *actionContext) EventID() uint64 {
+	return a.metadata.EventID
+}
+
+func (a *actionContext) JobName() string {
+	return a.metadata.JobName
+}
+
+func (a *actionContext) Occurrence() int {
+	return a.metadata.Occurrence
+}
+
+func (a *actionContext) Scheduler() timing.EventScheduler {
+	return a.scheduler
+}
+
 func (a *actionContext) Value(key any) any {
 	switch key {
 	case timing.ActionContextClockKey:
 		return a.clock
-	case ActionContextEventLoopBlockerKey:
+	case eventLoopBlockerKey{}, ActionContextEventLoopBlockerKey:
 		return a.eventLoopBlocker
 	default:
-		return a.Context.Value(key)
+		return a.values.Value(key)
 	}
 }
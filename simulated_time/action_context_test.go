@@ -14,7 +14,7 @@ func TestNewActionContext(t *testing.T) {
 
 	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
 
-	actionContextUnderTest := newActionContext(context.Background(), newClock(now), &sync.WaitGroup{})
+	actionContextUnderTest := newActionContext(context.Background(), newClock(now), &sync.WaitGroup{}, timing.EventMetadata{}, nil)
 	require.NotNil(t, actionContextUnderTest)
 	require.NotNil(t, actionContextUnderTest.Context)
 	require.NotNil(t, actionContextUnderTest.clock)
@@ -27,7 +27,7 @@ func TestActionContext_Clock(t *testing.T) {
 	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
 	clock := newClock(now)
 
-	actionContextUnderTest := newActionContext(context.Background(), clock, &sync.WaitGroup{})
+	actionContextUnderTest := newActionContext(context.Background(), clock, &sync.WaitGroup{}, timing.EventMetadata{}, nil)
 	gotClock := actionContextUnderTest.Clock()
 	require.Equal(t, clock, gotClock)
 }
@@ -37,7 +37,7 @@ func TestActionContext_DoneSchedulingNewEvents_blockerIsNil(t *testing.T) {
 
 	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
 
-	actionContextUnderTest := newActionContext(context.Background(), newClock(now), nil)
+	actionContextUnderTest := newActionContext(context.Background(), newClock(now), nil, timing.EventMetadata{}, nil)
 	actionContextUnderTest.DoneSchedulingNewEvents()
 }
 
@@ -47,7 +47,7 @@ func TestActionContext_DoneSchedulingNewEvents_blockerNotNil(t *testing.T) {
 	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
 	eventLoopBlocker := &sync.WaitGroup{}
 
-	actionContextUnderTest := newActionContext(context.Background(), newClock(now), eventLoopBlocker)
+	actionContextUnderTest := newActionContext(context.Background(), newClock(now), eventLoopBlocker, timing.EventMetadata{}, nil)
 
 	eventLoopBlocker.Add(1)
 	go func() {
@@ -62,9 +62,10 @@ func TestActionContext_Value_Clock(t *testing.T) {
 	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
 	clock := newClock(now)
 
-	actionContextUnderTest := newActionContext(context.Background(), clock, &sync.WaitGroup{})
+	actionContextUnderTest := newActionContext(timing.WithClock(context.Background(), NewManualClock(now)), clock, &sync.WaitGroup{}, timing.EventMetadata{}, nil)
 	gotClock := actionContextUnderTest.Value(timing.ActionContextClockKey)
 	require.Equal(t, clock, gotClock)
+	require.Equal(t, clock, timing.ClockFrom(actionContextUnderTest))
 }
 
 func TestActionContext_Value_EventLoopBlocker(t *testing.T) {
@@ -73,7 +74,7 @@ func TestActionContext_Value_EventLoopBlocker(t *testing.T) {
 	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
 	eventLoopBlocker := &sync.WaitGroup{}
 
-	actionContextUnderTest := newActionContext(context.Background(), newClock(now), eventLoopBlocker)
+	actionContextUnderTest := newActionContext(context.Background(), newClock(now), eventLoopBlocker, timing.EventMetadata{}, nil)
 	gotEventLoopBlocker := actionContextUnderTest.Value(ActionContextEventLoopBlockerKey)
 	require.Equal(t, eventLoopBlocker, gotEventLoopBlocker)
 }
@@ -83,7 +84,48 @@ func TestActionContext_Value_Default(t *testing.T) {
 
 	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
 
-	actionContextUnderTest := newActionContext(context.Background(), newClock(now), &sync.WaitGroup{})
+	actionContextUnderTest := newActionContext(context.Background(), newClock(now), &sync.WaitGroup{}, timing.EventMetadata{}, nil)
 	gotValue := actionContextUnderTest.Value("someNoneExistentKey")
 	require.Nil(t, gotValue)
 }
+
+func TestActionContext_Metadata(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	metadata := timing.EventMetadata{ScheduledTime: now, JobName: "job", EventID: 3, Occurrence: 2}
+	scheduler := NewSerialEventScheduler(now)
+
+	actionContextUnderTest := newActionContext(context.Background(), newClock(now), nil, metadata, scheduler)
+	require.Equal(t, metadata, actionContextUnderTest.Metadata())
+	require.Equal(t, now, actionContextUnderTest.ScheduledTime())
+	require.Equal(t, uint64(3), actionContextUnderTest.EventID())
+	require.Equal(t, "job", actionContextUnderTest.JobName())
+	require.Equal(t, 2, actionContextUnderTest.Occurrence())
+	require.Same(t, scheduler, actionContextUnderTest.Scheduler())
+}
+
+func TestActionContext_Scheduler_override(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	override := NewSerialEventScheduler(now)
+	ctx := timing.WithScheduler(context.Background(), override)
+
+	actionContextUnderTest := newActionContext(ctx, newClock(now), nil, timing.EventMetadata{}, NewSerialEventScheduler(now))
+	require.Same(t, override, actionContextUnderTest.Scheduler())
+}
+
+func TestEventLoopBlockerFrom(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	eventLoopBlocker := &sync.WaitGroup{}
+
+	actionContext := newActionContext(context.Background(), newClock(now), eventLoopBlocker, timing.EventMetadata{}, nil)
+	require.Same(t, eventLoopBlocker, EventLoopBlockerFrom(actionContext))
+	require.Same(t, eventLoopBlocker, EventLoopBlockerFrom(timing.DeriveActionContext(actionContext, context.WithValue(actionContext, "key", "value"))))
+
+	require.Nil(t, EventLoopBlockerFrom(newActionContext(context.Background(), newClock(now), nil, timing.EventMetadata{}, nil)))
+	require.Nil(t, EventLoopBlockerFrom(context.Background()))
+}
@@ -1,10 +1,17 @@
 package simulated_time
 
 import (
+	"github.com/metamogul/timing"
+	"github.com/metamogul/timing/internal/instrument"
+	"sync"
 	"time"
 )
 
+// clock is the time of a simulated scheduler. It is only set by the
+// goroutine forwarding the scheduler, but may be read from any goroutine,
+// e.g. by actions that schedule new events while the scheduler forwards.
 type clock struct {
+	mu  sync.RWMutex
 	now time.Time
 }
 
@@ -15,10 +22,16 @@ func newClock(now time.Time) *clock {
 }
 
 func (c *clock) Now() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
 	return c.now
 }
 
 func (c *clock) set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if t.Before(c.now) {
 		panic("time can't be in the past")
 	}
@@ -26,6 +39,17 @@ func (c *clock) set(t time.Time) {
 	c.now = t
 }
 
+// advance sets the clock to metadata.ScheduledTime and calls the
+// OnClockAdvanced hook if that moves it forward.
+func (c *clock) advance(metadata timing.EventMetadata, hooks *timing.Hooks) {
+	previous := c.Now()
+	c.set(metadata.ScheduledTime)
+
+	if metadata.ScheduledTime.After(previous) {
+		instrument.ClockAdvanced(hooks, metadata)
+	}
+}
+
 func (c *clock) copy() *clock {
-	return &clock{c.now}
+	return newClock(c.Now())
 }
@@ -0,0 +1,170 @@
+package simulated_time
+
+import (
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/metamogul/timing"
+)
+
+// The clocks in this file distort the time of the clock they wrap, usually a
+// simulated scheduler, so that each component of a simulation can be given
+// its own imperfect view of time. They can be stacked, e.g. a CoarseClock
+// around a DriftingClock around a SkewedClock. All of them are safe for
+// concurrent use as long as the wrapped clock is.
+
+// SkewedClock is off from the clock it wraps by a constant duration.
+type SkewedClock struct {
+	clock timing.Clock
+	skew  time.Duration
+}
+
+func NewSkewedClock(clock timing.Clock, skew time.Duration) *SkewedClock {
+	if clock == nil {
+		panic("clock can't be nil")
+	}
+
+	return &SkewedClock{
+		clock: clock,
+		skew:  skew,
+	}
+}
+
+func (s *SkewedClock) Now() time.Time {
+	return s.clock.Now().Add(s.skew)
+}
+
+// DriftingClock runs faster or slower than the clock it wraps by a constant
+// rate given in parts per million. It agrees with the wrapped clock at the
+// time it was created, and a positive ppm makes it run ahead from there.
+type DriftingClock struct {
+	clock  timing.Clock
+	origin time.Time
+	ppm    float64
+}
+
+func NewDriftingClock(clock timing.Clock, ppm float64) *DriftingClock {
+	if clock == nil {
+		panic("clock can't be nil")
+	}
+
+	return &DriftingClock{
+		clock:  clock,
+		origin: clock.Now(),
+		ppm:    ppm,
+	}
+}
+
+func (d *DriftingClock) Now() time.Time {
+	now := d.clock.Now()
+	drift := time.Duration(float64(now.Sub(d.origin)) * d.ppm / 1e6)
+
+	return now.Add(drift)
+}
+
+// JitteryClock adds a random offset of at most jitter in either direction
+// to every reading of the clock it wraps. The same seed yields the same
+// offsets.
+type JitteryClock struct {
+	clock  timing.Clock
+	jitter time.Duration
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+func NewJitteryClock(clock timing.Clock, jitter time.Duration, seed uint64) *JitteryClock {
+	if clock == nil {
+		panic("clock can't be nil")
+	}
+
+	if jitter < 0 {
+		panic("jitter can't be negative")
+	}
+
+	return &JitteryClock{
+		clock:  clock,
+		jitter: jitter,
+		rng:    rand.New(rand.NewPCG(seed, seed)),
+	}
+}
+
+func (j *JitteryClock) Now() time.Time {
+	j.mu.Lock()
+	offset := time.Duration(j.rng.Int64N(2*int64(j.jitter)+1)) - j.jitter
+	j.mu.Unlock()
+
+	return j.clock.Now().Add(offset)
+}
+
+// CoarseClock only advances in steps of resolution, like a system clock that
+// is updated by a 15ms timer interrupt.
+type CoarseClock struct {
+	clock      timing.Clock
+	resolution time.Duration
+}
+
+func NewCoarseClock(clock timing.Clock, resolution time.Duration) *CoarseClock {
+	if clock == nil {
+		panic("clock can't be nil")
+	}
+
+	if resolution <= 0 {
+		panic("resolution must be greater than zero")
+	}
+
+	return &CoarseClock{
+		clock:      clock,
+		resolution: resolution,
+	}
+}
+
+func (c *CoarseClock) Now() time.Time {
+	return c.clock.Now().Truncate(c.resolution)
+}
+
+// SteppingClock occasionally steps backward, like a clock corrected by NTP.
+// Every reading steps it back by up to maxStep with the given probability,
+// and it stays behind the clock it wraps by the sum of all steps so far.
+type SteppingClock struct {
+	clock       timing.Clock
+	probability float64
+	maxStep     time.Duration
+
+	mu     sync.Mutex
+	rng    *rand.Rand
+	offset time.Duration
+}
+
+func NewSteppingClock(clock timing.Clock, probability float64, maxStep time.Duration, seed uint64) *SteppingClock {
+	if clock == nil {
+		panic("clock can't be nil")
+	}
+
+	if probability < 0 || probability > 1 {
+		panic("probability must be between 0 and 1")
+	}
+
+	if maxStep <= 0 {
+		panic("maxStep must be greater than zero")
+	}
+
+	return &SteppingClock{
+		clock:       clock,
+		probability: probability,
+		maxStep:     maxStep,
+		rng:         rand.New(rand.NewPCG(seed, seed)),
+	}
+}
+
+func (s *SteppingClock) Now() time.Time {
+	s.mu.Lock()
+	if s.rng.Float64() < s.probability {
+		s.offset -= time.Duration(s.rng.Int64N(int64(s.maxStep))) + 1
+	}
+	offset := s.offset
+	s.mu.Unlock()
+
+	return s.clock.Now().Add(offset)
+}
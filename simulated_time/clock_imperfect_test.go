@@ -0,0 +1,139 @@
+package simulated_time
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSkewedClock_Now(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	manualClock := NewManualClock(now)
+	clock := NewSkewedClock(manualClock, -time.Second)
+
+	require.Equal(t, now.Add(-time.Second), clock.Now())
+
+	manualClock.Advance(time.Hour)
+	require.Equal(t, now.Add(time.Hour-time.Second), clock.Now())
+
+	require.PanicsWithValue(t, "clock can't be nil", func() { NewSkewedClock(nil, 0) })
+}
+
+func TestDriftingClock_Now(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		ppm      float64
+		elapsed  time.Duration
+		expected time.Duration
+	}{
+		{name: "at origin", ppm: 100, elapsed: 0, expected: 0},
+		{name: "running fast", ppm: 100, elapsed: 10000 * time.Second, expected: 10001 * time.Second},
+		{name: "running slow", ppm: -50, elapsed: 20000 * time.Second, expected: 19999 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			manualClock := NewManualClock(now)
+			clock := NewDriftingClock(manualClock, tt.ppm)
+
+			manualClock.Advance(tt.elapsed)
+			require.Equal(t, now.Add(tt.expected), clock.Now())
+		})
+	}
+}
+
+func TestJitteryClock_Now(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	jitter := 5 * time.Millisecond
+
+	clock := NewJitteryClock(NewManualClock(now), jitter, 42)
+	sameSeedClock := NewJitteryClock(NewManualClock(now), jitter, 42)
+
+	distinct := make(map[time.Time]struct{})
+	for range 100 {
+		reading := clock.Now()
+		require.Equal(t, reading, sameSeedClock.Now())
+		require.False(t, reading.Before(now.Add(-jitter)))
+		require.False(t, reading.After(now.Add(jitter)))
+
+		distinct[reading] = struct{}{}
+	}
+	require.Greater(t, len(distinct), 1)
+
+	require.Equal(t, now, NewJitteryClock(NewManualClock(now), 0, 42).Now())
+	require.PanicsWithValue(t, "jitter can't be negative", func() { NewJitteryClock(NewManualClock(now), -1, 0) })
+}
+
+func TestCoarseClock_Now(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	manualClock := NewManualClock(now)
+	clock := NewCoarseClock(manualClock, 15*time.Millisecond)
+
+	manualClock.Advance(14 * time.Millisecond)
+	require.Equal(t, now, clock.Now())
+
+	manualClock.Advance(time.Millisecond)
+	require.Equal(t, now.Add(15*time.Millisecond), clock.Now())
+
+	manualClock.Advance(29 * time.Millisecond)
+	require.Equal(t, now.Add(30*time.Millisecond), clock.Now())
+
+	require.PanicsWithValue(t, "resolution must be greater than zero", func() { NewCoarseClock(manualClock, 0) })
+}
+
+func TestSteppingClock_Now(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	manualClock := NewManualClock(now)
+	clock := NewSteppingClock(manualClock, 0.2, time.Second, 7)
+
+	steps := 0
+	previous := clock.Now()
+	for range 100 {
+		manualClock.Advance(time.Millisecond)
+
+		reading := clock.Now()
+		if reading.Before(previous) {
+			steps++
+			require.False(t, reading.Before(previous.Add(-time.Second)))
+		}
+		require.False(t, reading.After(manualClock.Now()))
+
+		previous = reading
+	}
+	require.Greater(t, steps, 0)
+
+	require.Equal(t, now, NewSteppingClock(NewManualClock(now), 0, time.Second, 7).Now())
+	require.PanicsWithValue(t, "probability must be between 0 and 1", func() { NewSteppingClock(manualClock, 2, time.Second, 7) })
+	require.PanicsWithValue(t, "maxStep must be greater than zero", func() { NewSteppingClock(manualClock, 0.5, 0, 7) })
+}
+
+func TestImperfectClocks_stacked(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	eventScheduler := NewSerialEventScheduler(now)
+	clock := NewCoarseClock(NewDriftingClock(NewSkewedClock(eventScheduler, 3*time.Millisecond), 10000), 10*time.Millisecond)
+
+	require.Equal(t, now, clock.Now())
+
+	eventScheduler.Forward(time.Second)
+	require.Equal(t, now.Add(time.Second+10*time.Millisecond), clock.Now())
+}
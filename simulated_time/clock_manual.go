@@ -0,0 +1,44 @@
+package simulated_time
+
+import (
+	"sync"
+	"time"
+)
+
+// ManualClock is a timing.Clock for code that only needs Now. Its time only
+// changes through Set and Advance, and it is safe for concurrent use.
+type ManualClock struct {
+	mu  sync.RWMutex
+	now time.Time
+}
+
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{
+		now: now,
+	}
+}
+
+func (m *ManualClock) Now() time.Time {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.now
+}
+
+// Set moves the clock to t, which may also be in the past.
+func (m *ManualClock) Set(t time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.now = t
+}
+
+// Advance moves the clock forward by d and returns the new time.
+func (m *ManualClock) Advance(d time.Duration) time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.now = m.now.Add(d)
+
+	return m.now
+}
@@ -0,0 +1,70 @@
+package simulated_time
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewManualClock(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	clock := NewManualClock(now)
+	require.NotNil(t, clock)
+	require.Equal(t, now, clock.Now())
+}
+
+func TestManualClock_Set(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	clock := NewManualClock(now)
+
+	clock.Set(now.Add(time.Hour))
+	require.Equal(t, now.Add(time.Hour), clock.Now())
+
+	clock.Set(now.Add(-time.Hour))
+	require.Equal(t, now.Add(-time.Hour), clock.Now())
+}
+
+func TestManualClock_Advance(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	clock := NewManualClock(now)
+
+	require.Equal(t, now.Add(time.Minute), clock.Advance(time.Minute))
+	require.Equal(t, now.Add(time.Minute), clock.Now())
+}
+
+func TestManualClock_concurrentAccess(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	clock := NewManualClock(now)
+
+	wg := sync.WaitGroup{}
+	for range 10 {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			clock.Advance(time.Second)
+		}()
+
+		go func() {
+			defer wg.Done()
+			require.False(t, clock.Now().Before(now))
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, now.Add(10*time.Second), clock.Now())
+}
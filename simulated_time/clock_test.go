@@ -2,6 +2,7 @@ package simulated_time
 
 import (
 	"github.com/stretchr/testify/require"
+	"sync"
 	"testing"
 	"time"
 )
@@ -22,7 +23,7 @@ func TestClock_Now(t *testing.T) {
 
 	now := time.Now()
 
-	clock := clock{now}
+	clock := clock{now: now}
 	require.Equal(t, now, clock.Now())
 }
 
@@ -75,3 +76,29 @@ func Test_clock_Set(t *testing.T) {
 		})
 	}
 }
+
+func Test_clock_concurrentAccess(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := newClock(now)
+
+	wg := sync.WaitGroup{}
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				require.False(t, c.Now().Before(now))
+				_ = c.copy()
+			}
+		}()
+	}
+
+	for i := range 100 {
+		c.set(now.Add(time.Duration(i) * time.Second))
+	}
+	wg.Wait()
+
+	require.Equal(t, now.Add(99*time.Second), c.Now())
+}
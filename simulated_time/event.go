@@ -11,6 +11,21 @@ type Event struct {
 	timing.Action
 	time.Time
 	context.Context
+
+	// Occurrence counts the events of a generator from 1. Generators that
+	// don't count their events leave it 0.
+	Occurrence int
+}
+
+// eventMetadata describes event, which is the id-th event performed by its
+// scheduler.
+func eventMetadata(event *Event, id uint64) timing.EventMetadata {
+	return timing.EventMetadata{
+		ScheduledTime: event.Time,
+		JobName:       timing.JobNameFrom(event.Context),
+		EventID:       id,
+		Occurrence:    event.Occurrence,
+	}
 }
 
 func NewEvent(action timing.Action, time time.Time, ctx context.Context) *Event {
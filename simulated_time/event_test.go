@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/metamogul/timing"
 	"github.com/stretchr/testify/require"
+	"math/rand/v2"
 	"reflect"
 	"testing"
 	"time"
@@ -65,10 +66,73 @@ func Test_newEvent(t *testing.T) {
 	}
 }
 
+func TestEvent_Occurrence(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	action := timing.ActionFunc(func(timing.ActionContext) {})
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		generator EventGenerator
+		want      []int
+	}{
+		{
+			name:      "single",
+			generator: Once(action, now, ctx),
+			want:      []int{1},
+		},
+		{
+			name:      "periodic",
+			generator: Every(action, now, time.Minute, ctx),
+			want:      []int{1, 2, 3},
+		},
+		{
+			name:      "explicit",
+			generator: Explicit([]TimedAction{{Time: now.Add(time.Hour), Action: action}, {Time: now, Action: action}}, ctx),
+			want:      []int{1, 2},
+		},
+		{
+			name:      "timing wheel",
+			generator: newTimingWheelEventGenerator(action, now, time.Minute, nil, time.Second, ctx),
+			want:      []int{1, 2, 3},
+		},
+		{
+			name:      "arrivals",
+			generator: PoissonArrivals(action, now, nil, time.Second, rand.NewPCG(1, 2), ctx),
+			want:      []int{1, 2, 3},
+		},
+		{
+			name:      "skipped",
+			generator: Skip(Every(action, now, time.Minute, ctx), 2),
+			want:      []int{3, 4, 5},
+		},
+		{
+			name:      "merged",
+			generator: Merge(Every(action, now, time.Minute, ctx), Once(action, now.Add(90*time.Second), ctx)),
+			want:      []int{1, 1, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			occurrences := make([]int, 0, len(tt.want))
+			for _, event := range Preview(tt.generator, len(tt.want)) {
+				occurrences = append(occurrences, event.Occurrence)
+			}
+
+			require.Equal(t, tt.want, occurrences)
+		})
+	}
+}
+
 func Test_event_perform(t *testing.T) {
 	t.Parallel()
 
-	actionContextArg := newActionContext(context.Background(), newClock(time.Now()), nil)
+	actionContextArg := newActionContext(context.Background(), newClock(time.Now()), nil, timing.EventMetadata{}, nil)
 
 	e := &Event{
 		Action: func() timing.Action {
@@ -1,7 +1,9 @@
 package simulated_time
 
 import (
+	"errors"
 	"slices"
+	"time"
 )
 
 type eventCombinator struct {
@@ -16,7 +18,7 @@ func newEventCombinator(inputs ...EventGenerator) *eventCombinator {
 	}
 
 	for _, input := range inputs {
-		if input.Finished() {
+		if generatorFinished(input) {
 			combinator.finishedGenerators = append(combinator.finishedGenerators, input)
 		} else {
 			combinator.activeGenerators = append(combinator.activeGenerators, input)
@@ -29,7 +31,7 @@ func newEventCombinator(inputs ...EventGenerator) *eventCombinator {
 }
 
 func (e *eventCombinator) add(generator EventGenerator) {
-	if generator.Finished() {
+	if generatorFinished(generator) {
 		e.finishedGenerators = append(e.finishedGenerators, generator)
 		return
 	}
@@ -40,36 +42,197 @@ func (e *eventCombinator) add(generator EventGenerator) {
 }
 
 func (e *eventCombinator) Pop() *Event {
-	if e.Finished() {
+	return e.popAmongEarliest(nil)
+}
+
+// popAmongEarliest pops the event of one of the generators whose next events
+// share the earliest time, letting choose pick among the n candidates.
+func (e *eventCombinator) popAmongEarliest(choose func(n int) int) *Event {
+	nextEvent := e.popUntil(nil, choose)
+	if nextEvent == nil {
 		panic(ErrEventGeneratorFinished)
 	}
 
-	nextEvent := e.activeGenerators[0].Pop()
+	return nextEvent
+}
 
-	if e.activeGenerators[0].Finished() {
-		e.finishedGenerators = append(e.finishedGenerators, e.activeGenerators[0])
-		e.activeGenerators = e.activeGenerators[1:]
-	}
+// popUntil pops the earliest event unless it is after targetTime, and returns
+// nil if there is none. A nil targetTime accepts any event, and a nil choose
+// pops from the first of the generators whose next events share the earliest
+// time.
+//
+// The context of a generator can be cancelled from another goroutine at any
+// time, which finishes the generator. popUntil thus doesn't rely on Finished
+// or an earlier Peek, but peeks every generator once, moves those that turned
+// out to be finished and tries again if the chosen one finished in between.
+func (e *eventCombinator) popUntil(targetTime *time.Time, choose func(n int) int) *Event {
+	for {
+		heads := e.peekActiveGenerators()
+		if len(heads) == 0 || (targetTime != nil && heads[0].After(*targetTime)) {
+			return nil
+		}
 
-	e.sortActiveGenerators()
+		candidates := 1
+		for candidates < len(heads) && heads[candidates].Time.Equal(heads[0].Time) {
+			candidates++
+		}
 
-	return nextEvent
+		chosen := 0
+		if choose != nil {
+			chosen = choose(candidates)
+		}
+
+		generator := e.activeGenerators[chosen]
+		nextEvent, popped := popGenerator(generator)
+
+		if !popped {
+			e.activeGenerators = slices.Delete(e.activeGenerators, chosen, chosen+1)
+			e.finish(generator)
+		} else if generatorFinished(generator) {
+			e.activeGenerators = slices.Delete(e.activeGenerators, chosen, chosen+1)
+			e.finishedGenerators = append(e.finishedGenerators, generator)
+		}
+
+		if popped {
+			e.sortActiveGenerators()
+			return nextEvent
+		}
+	}
 }
 
+// Peek skips generators that finished without being popped, e.g. because
+// their context was cancelled. That doesn't change the order of the others.
 func (e *eventCombinator) Peek() Event {
-	if e.Finished() {
+	nextEvent, ok := e.next()
+	if !ok {
 		panic(ErrEventGeneratorFinished)
 	}
 
-	return e.activeGenerators[0].Peek()
+	return nextEvent
+}
+
+// next is like Peek, but returns false instead of panicking if all
+// generators have finished.
+func (e *eventCombinator) next() (Event, bool) {
+	for _, generator := range e.activeGenerators {
+		if nextEvent, ok := peekGenerator(generator); ok {
+			return nextEvent, true
+		}
+	}
+
+	return Event{}, false
+}
+
+// Clone leaves out the finished generators.
+func (e *eventCombinator) Clone() EventGenerator {
+	return e.clone()
+}
+
+func (e *eventCombinator) clone() *eventCombinator {
+	clone := &eventCombinator{
+		activeGenerators:   make([]EventGenerator, 0, len(e.activeGenerators)),
+		finishedGenerators: make([]EventGenerator, 0),
+	}
+
+	for _, generator := range e.activeGenerators {
+		clone.activeGenerators = append(clone.activeGenerators, Clone(generator))
+	}
+
+	return clone
 }
 
 func (e *eventCombinator) Finished() bool {
-	return len(e.activeGenerators) == 0
+	return !slices.ContainsFunc(e.activeGenerators, func(generator EventGenerator) bool {
+		return !generatorFinished(generator)
+	})
 }
 
 func (e *eventCombinator) sortActiveGenerators() {
-	slices.SortStableFunc(e.activeGenerators, func(a, b EventGenerator) int {
-		return a.Peek().Time.Compare(b.Peek().Time)
+	if len(e.activeGenerators) < 2 {
+		return
+	}
+
+	e.peekActiveGenerators()
+}
+
+// peekActiveGenerators peeks every active generator once, moves those that
+// have finished, and sorts the others by the time of the returned events.
+func (e *eventCombinator) peekActiveGenerators() []Event {
+	type head struct {
+		generator EventGenerator
+		event     Event
+	}
+
+	heads := make([]head, 0, len(e.activeGenerators))
+	for _, generator := range e.activeGenerators {
+		if nextEvent, ok := peekGenerator(generator); ok {
+			heads = append(heads, head{generator, nextEvent})
+		} else {
+			e.finish(generator)
+		}
+	}
+
+	slices.SortStableFunc(heads, func(a, b head) int {
+		return a.event.Time.Compare(b.event.Time)
 	})
+
+	e.activeGenerators = make([]EventGenerator, len(heads))
+	events := make([]Event, len(heads))
+	for i, head := range heads {
+		e.activeGenerators[i] = head.generator
+		events[i] = head.event
+	}
+
+	return events
+}
+
+// finish moves generator to the finished generators. Generators that panicked
+// because they finished are asked once more, so that instrumented ones report
+// it.
+func (e *eventCombinator) finish(generator EventGenerator) {
+	generatorFinished(generator)
+	e.finishedGenerators = append(e.finishedGenerators, generator)
+}
+
+// generatorFinished also treats generators as finished that panic while
+// finding out, like those that peek at a generator that finished in between.
+func generatorFinished(generator EventGenerator) (finished bool) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			if !isFinishedPanic(recovered) {
+				panic(recovered)
+			}
+			finished = true
+		}
+	}()
+
+	return generator.Finished()
+}
+
+func peekGenerator(generator EventGenerator) (nextEvent Event, ok bool) {
+	defer recoverFinished(&ok)
+
+	return generator.Peek(), true
+}
+
+func popGenerator(generator EventGenerator) (nextEvent *Event, ok bool) {
+	defer recoverFinished(&ok)
+
+	return generator.Pop(), true
+}
+
+// recoverFinished recovers from a generator panicking because it finished,
+// and sets ok to false.
+func recoverFinished(ok *bool) {
+	if recovered := recover(); recovered != nil {
+		if !isFinishedPanic(recovered) {
+			panic(recovered)
+		}
+		*ok = false
+	}
+}
+
+func isFinishedPanic(recovered any) bool {
+	err, isError := recovered.(error)
+	return isError && errors.Is(err, ErrEventGeneratorFinished)
 }
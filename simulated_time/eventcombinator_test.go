@@ -235,9 +235,10 @@ func Test_eventCombinator_pop(t *testing.T) {
 			},
 			finishesGenerator: false,
 			want: &Event{
-				Action:  timing.NewMockAction(t),
-				Time:    time.Time{}.Add(time.Second),
-				Context: ctx,
+				Action:     timing.NewMockAction(t),
+				Time:       time.Time{}.Add(time.Second),
+				Context:    ctx,
+				Occurrence: 1,
 			},
 		},
 		{
@@ -254,9 +255,10 @@ func Test_eventCombinator_pop(t *testing.T) {
 			},
 			finishesGenerator: true,
 			want: &Event{
-				Action:  timing.NewMockAction(t),
-				Time:    time.Time{},
-				Context: ctx,
+				Action:     timing.NewMockAction(t),
+				Time:       time.Time{},
+				Context:    ctx,
+				Occurrence: 1,
 			},
 		},
 	}
@@ -334,9 +336,10 @@ func Test_eventCombinator_peek(t *testing.T) {
 				},
 			},
 			want: Event{
-				Action:  timing.NewMockAction(t),
-				Time:    time.Time{}.Add(time.Second),
-				Context: ctx,
+				Action:     timing.NewMockAction(t),
+				Time:       time.Time{}.Add(time.Second),
+				Context:    ctx,
+				Occurrence: 1,
 			},
 		},
 	}
@@ -385,7 +388,14 @@ func Test_eventCombinator_finished(t *testing.T) {
 		{
 			name: "not finished",
 			fields: fields{
-				activeGenerators:   []EventGenerator{NewMockEventGenerator(t)},
+				activeGenerators: func() []EventGenerator {
+					mockEventGenerator := NewMockEventGenerator(t)
+					mockEventGenerator.EXPECT().
+						Finished().
+						Return(false)
+
+					return []EventGenerator{mockEventGenerator}
+				}(),
 				finishedGenerators: make([]EventGenerator, 0),
 			},
 			want: false,
@@ -416,6 +426,61 @@ func Test_eventCombinator_finished(t *testing.T) {
 	}
 }
 
+func Test_eventCombinator_finished_cancelledGenerator(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	eventGenerator1 := newSingleEventGenerator(timing.NewMockAction(t), time.Time{}, ctx)
+	eventGenerator2 := newSingleEventGenerator(timing.NewMockAction(t), time.Time{}.Add(time.Second), context.Background())
+
+	e := newEventCombinator(eventGenerator1, eventGenerator2)
+
+	cancel()
+
+	require.False(t, e.Finished())
+	require.Equal(t, time.Time{}.Add(time.Second), e.Peek().Time)
+	require.Len(t, e.activeGenerators, 2)
+
+	require.Equal(t, time.Time{}.Add(time.Second), e.Pop().Time)
+	require.Empty(t, e.activeGenerators)
+	require.Equal(t, []EventGenerator{eventGenerator1, eventGenerator2}, e.finishedGenerators)
+	require.True(t, e.Finished())
+}
+
+func Test_eventCombinator_popAmongEarliest(t *testing.T) {
+	t.Parallel()
+
+	action1 := timing.NewMockAction(t)
+	action2 := timing.NewMockAction(t)
+	action3 := timing.NewMockAction(t)
+
+	e := newEventCombinator(
+		newSingleEventGenerator(action1, time.Time{}.Add(time.Second), context.Background()),
+		newSingleEventGenerator(action2, time.Time{}.Add(time.Second), context.Background()),
+		newSingleEventGenerator(action3, time.Time{}.Add(time.Minute), context.Background()),
+	)
+
+	candidates := 0
+	event := e.popAmongEarliest(func(n int) int {
+		candidates = n
+		return 1
+	})
+	require.Equal(t, 2, candidates)
+	require.Same(t, action2, event.Action)
+
+	event = e.popAmongEarliest(func(n int) int {
+		candidates = n
+		return 0
+	})
+	require.Equal(t, 1, candidates)
+	require.Same(t, action1, event.Action)
+
+	require.Same(t, action3, e.popAmongEarliest(func(int) int { return 0 }).Action)
+	require.True(t, e.Finished())
+	require.PanicsWithValue(t, ErrEventGeneratorFinished, func() { e.popAmongEarliest(func(int) int { return 0 }) })
+}
+
 func Test_eventCombinator_sortActiveGeneratos(t *testing.T) {
 	t.Parallel()
 
@@ -436,3 +501,65 @@ func Test_eventCombinator_sortActiveGeneratos(t *testing.T) {
 	})
 	require.True(t, sorted)
 }
+
+func Test_eventCombinator_popUntil(t *testing.T) {
+	t.Parallel()
+
+	panicFinished := func() Event { panic(ErrEventGeneratorFinished) }
+
+	t.Run("finished before peek", func(t *testing.T) {
+		t.Parallel()
+
+		finishing := NewMockEventGenerator(t)
+		finishing.EXPECT().Peek().RunAndReturn(panicFinished).Once()
+		finishing.EXPECT().Finished().Return(true).Once()
+
+		action := timing.NewMockAction(t)
+		other := newSingleEventGenerator(action, time.Time{}.Add(time.Second), context.Background())
+
+		e := &eventCombinator{activeGenerators: []EventGenerator{finishing, other}, finishedGenerators: make([]EventGenerator, 0)}
+
+		targetTime := time.Time{}.Add(time.Minute)
+		require.Same(t, action, e.popUntil(&targetTime, nil).Action)
+		require.Empty(t, e.activeGenerators)
+		require.Equal(t, []EventGenerator{finishing, other}, e.finishedGenerators)
+	})
+
+	t.Run("finished between peek and pop", func(t *testing.T) {
+		t.Parallel()
+
+		finishing := NewMockEventGenerator(t)
+		finishing.EXPECT().Peek().Return(Event{Time: time.Time{}}).Once()
+		finishing.EXPECT().Pop().RunAndReturn(func() *Event { panic(ErrEventGeneratorFinished) }).Once()
+		finishing.EXPECT().Finished().Return(true).Once()
+
+		action := timing.NewMockAction(t)
+		other := newSingleEventGenerator(action, time.Time{}.Add(time.Second), context.Background())
+
+		e := &eventCombinator{activeGenerators: []EventGenerator{finishing, other}, finishedGenerators: make([]EventGenerator, 0)}
+
+		require.Same(t, action, e.popUntil(nil, nil).Action)
+		require.Equal(t, []EventGenerator{finishing, other}, e.finishedGenerators)
+	})
+
+	t.Run("after target time", func(t *testing.T) {
+		t.Parallel()
+
+		e := newEventCombinator(newSingleEventGenerator(timing.NewMockAction(t), time.Time{}.Add(time.Minute), context.Background()))
+
+		targetTime := time.Time{}.Add(time.Second)
+		require.Nil(t, e.popUntil(&targetTime, nil))
+		require.Len(t, e.activeGenerators, 1)
+	})
+
+	t.Run("other panics", func(t *testing.T) {
+		t.Parallel()
+
+		panicking := NewMockEventGenerator(t)
+		panicking.EXPECT().Peek().RunAndReturn(func() Event { panic("broken") }).Once()
+
+		e := &eventCombinator{activeGenerators: []EventGenerator{panicking}, finishedGenerators: make([]EventGenerator, 0)}
+
+		require.PanicsWithValue(t, "broken", func() { e.popUntil(nil, nil) })
+	})
+}
@@ -2,9 +2,13 @@ package simulated_time
 
 import (
 	"errors"
+	"github.com/metamogul/timing"
 )
 
-var ErrEventGeneratorFinished = errors.New("event generator is finished")
+var (
+	ErrEventGeneratorFinished     = errors.New("event generator is finished")
+	ErrEventGeneratorNotCloneable = errors.New("event generator can't be cloned")
+)
 
 type EventGenerator interface {
 	Pop() *Event
@@ -12,3 +16,20 @@ type EventGenerator interface {
 
 	Finished() bool
 }
+
+// CloneableEventGenerator is an EventGenerator that can be copied, so that
+// its upcoming events can be looked at without consuming them. All generators
+// of this package are cloneable, as long as the generators they wrap are.
+type CloneableEventGenerator interface {
+	EventGenerator
+
+	// Clone returns an independent generator yielding the same events from
+	// here on. It panics with ErrEventGeneratorNotCloneable if that's not
+	// possible.
+	Clone() EventGenerator
+}
+
+type GeneratorScheduler interface {
+	timing.EventScheduler
+	AddGenerator(generator EventGenerator)
+}
@@ -0,0 +1,264 @@
+package simulated_time
+
+import (
+	"context"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/metamogul/timing"
+)
+
+// arrivalEventGenerator yields events at random times. Like
+// periodicEventGenerator, it finishes once its context is cancelled or its
+// next event would be after until, if given.
+type arrivalEventGenerator struct {
+	action timing.Action
+	until  *time.Time
+	ctx    context.Context
+
+	source  rand.Source
+	rng     *rand.Rand
+	process arrivalProcess
+
+	currentEvent *Event
+}
+
+// arrivalProcess decides when arrivals happen.
+type arrivalProcess interface {
+	// nextArrival returns the first arrival after the given time.
+	nextArrival(rng *rand.Rand, after time.Time) time.Time
+	clone() arrivalProcess
+}
+
+func newArrivalEventGenerator(
+	action timing.Action,
+	from time.Time,
+	until *time.Time,
+	source rand.Source,
+	process arrivalProcess,
+	ctx context.Context,
+) *arrivalEventGenerator {
+	if action == nil {
+		panic("Action can't be nil")
+	}
+
+	if until != nil && !until.After(from) {
+		panic("until must be after from")
+	}
+
+	rng := rand.New(source)
+
+	firstEvent := NewEvent(action, process.nextArrival(rng, from), ctx)
+	firstEvent.Occurrence = 1
+
+	return &arrivalEventGenerator{
+		action:       action,
+		until:        until,
+		ctx:          ctx,
+		source:       source,
+		rng:          rng,
+		process:      process,
+		currentEvent: firstEvent,
+	}
+}
+
+func (a *arrivalEventGenerator) Pop() *Event {
+	if a.Finished() {
+		panic(ErrEventGeneratorFinished)
+	}
+
+	event := a.currentEvent
+
+	a.currentEvent = NewEvent(a.action, a.process.nextArrival(a.rng, event.Time), a.ctx)
+	a.currentEvent.Occurrence = event.Occurrence + 1
+
+	return event
+}
+
+func (a *arrivalEventGenerator) Peek() Event {
+	if a.Finished() {
+		panic(ErrEventGeneratorFinished)
+	}
+
+	return *a.currentEvent
+}
+
+func (a *arrivalEventGenerator) Finished() bool {
+	if a.ctx.Err() != nil {
+		return true
+	}
+
+	return a.until != nil && a.currentEvent.After(*a.until)
+}
+
+// Clone requires the source to be a *rand.PCG or a *rand.ChaCha8, the
+// sources of math/rand/v2 whose state can be copied.
+func (a *arrivalEventGenerator) Clone() EventGenerator {
+	var source rand.Source
+	switch s := a.source.(type) {
+	case *rand.PCG:
+		pcg := *s
+		source = &pcg
+	case *rand.ChaCha8:
+		chaCha8 := *s
+		source = &chaCha8
+	default:
+		panic(ErrEventGeneratorNotCloneable)
+	}
+
+	clone := *a
+	clone.source = source
+	clone.rng = rand.New(source)
+	clone.process = a.process.clone()
+
+	return &clone
+}
+
+// gapProcess has independent gaps between arrivals, negative gaps counting
+// as none.
+type gapProcess func(rng *rand.Rand) time.Duration
+
+func (g gapProcess) nextArrival(rng *rand.Rand, after time.Time) time.Time {
+	return after.Add(max(0, g(rng)))
+}
+
+func (g gapProcess) clone() arrivalProcess {
+	return g
+}
+
+func exponential(rng *rand.Rand, mean time.Duration) time.Duration {
+	return time.Duration(rng.ExpFloat64() * float64(mean))
+}
+
+// PoissonArrivals returns a generator of a Poisson process: arrivals with
+// exponentially distributed gaps of mean length. The first arrival is after
+// from, and from source the same arrivals follow every time.
+func PoissonArrivals(action timing.Action, from time.Time, until *time.Time, mean time.Duration, source rand.Source, ctx context.Context) EventGenerator {
+	if mean <= 0 {
+		panic("mean must be greater than zero")
+	}
+
+	return newArrivalEventGenerator(action, from, until, source, gapProcess(func(rng *rand.Rand) time.Duration {
+		return exponential(rng, mean)
+	}), ctx)
+}
+
+// UniformArrivals returns a generator of arrivals with gaps drawn evenly from
+// [low, high].
+func UniformArrivals(action timing.Action, from time.Time, until *time.Time, low, high time.Duration, source rand.Source, ctx context.Context) EventGenerator {
+	if low < 0 || high < low {
+		panic("low must not be negative nor greater than high")
+	}
+
+	if high == 0 {
+		panic("high must be greater than zero")
+	}
+
+	return newArrivalEventGenerator(action, from, until, source, gapProcess(func(rng *rand.Rand) time.Duration {
+		return low + time.Duration(rng.Int64N(int64(high-low)+1))
+	}), ctx)
+}
+
+// NormalArrivals returns a generator of arrivals with normally distributed
+// gaps. Negative gaps are cut off, making arrivals coincide.
+func NormalArrivals(action timing.Action, from time.Time, until *time.Time, mean, stddev time.Duration, source rand.Source, ctx context.Context) EventGenerator {
+	if mean < 0 || stddev < 0 {
+		panic("mean and stddev can't be negative")
+	}
+
+	if mean == 0 {
+		panic("mean must be greater than zero")
+	}
+
+	return newArrivalEventGenerator(action, from, until, source, gapProcess(func(rng *rand.Rand) time.Duration {
+		return mean + time.Duration(rng.NormFloat64()*float64(stddev))
+	}), ctx)
+}
+
+// LogNormalArrivals returns a generator of arrivals with log-normally
+// distributed gaps, i.e. gaps whose logarithm is normally distributed with a
+// standard deviation of sigma. Half of the gaps are shorter than median.
+func LogNormalArrivals(action timing.Action, from time.Time, until *time.Time, median time.Duration, sigma float64, source rand.Source, ctx context.Context) EventGenerator {
+	if median <= 0 {
+		panic("median must be greater than zero")
+	}
+
+	if sigma < 0 {
+		panic("sigma can't be negative")
+	}
+
+	return newArrivalEventGenerator(action, from, until, source, gapProcess(func(rng *rand.Rand) time.Duration {
+		return time.Duration(float64(median) * math.Exp(sigma*rng.NormFloat64()))
+	}), ctx)
+}
+
+// ArrivalPhase is a phase of BurstyArrivals.
+type ArrivalPhase struct {
+	// MeanGap is the mean time between arrivals during the phase. Zero means
+	// no arrivals at all.
+	MeanGap time.Duration
+	// MeanDuration is the mean length of the phase, which is exponentially
+	// distributed.
+	MeanDuration time.Duration
+}
+
+// BurstyArrivals returns a generator of a Markov-modulated Poisson process,
+// which cycles through phases, starting with the first one at from. During
+// each phase, arrivals form a Poisson process with the phase's MeanGap. An
+// on/off process consists of a phase with and a phase without arrivals.
+func BurstyArrivals(action timing.Action, from time.Time, until *time.Time, phases []ArrivalPhase, source rand.Source, ctx context.Context) EventGenerator {
+	if len(phases) == 0 {
+		panic("phases can't be empty")
+	}
+
+	hasArrivals := false
+	for _, phase := range phases {
+		if phase.MeanGap < 0 || phase.MeanDuration <= 0 {
+			panic("phases need a positive MeanDuration and a MeanGap that isn't negative")
+		}
+
+		hasArrivals = hasArrivals || phase.MeanGap > 0
+	}
+
+	if !hasArrivals {
+		panic("at least one phase needs arrivals")
+	}
+
+	return newArrivalEventGenerator(action, from, until, source, &burstyProcess{phases: phases, phaseEnd: from}, ctx)
+}
+
+// burstyProcess cycles through phases. The first phase starts at the initial
+// phaseEnd, and its end is drawn along with the first arrival.
+type burstyProcess struct {
+	phases   []ArrivalPhase
+	phase    int
+	phaseEnd time.Time
+	started  bool
+}
+
+// nextArrival simply redraws an arrival beyond the end of the phase in the
+// next one, since the exponential gaps are memoryless.
+func (b *burstyProcess) nextArrival(rng *rand.Rand, after time.Time) time.Time {
+	if !b.started {
+		b.phaseEnd = b.phaseEnd.Add(exponential(rng, b.phases[0].MeanDuration))
+		b.started = true
+	}
+
+	for {
+		if meanGap := b.phases[b.phase].MeanGap; meanGap > 0 {
+			if arrival := after.Add(exponential(rng, meanGap)); !arrival.After(b.phaseEnd) {
+				return arrival
+			}
+		}
+
+		after = b.phaseEnd
+		b.phase = (b.phase + 1) % len(b.phases)
+		b.phaseEnd = b.phaseEnd.Add(exponential(rng, b.phases[b.phase].MeanDuration))
+	}
+}
+
+func (b *burstyProcess) clone() arrivalProcess {
+	clone := *b
+	return &clone
+}
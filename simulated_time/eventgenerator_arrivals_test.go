@@ -0,0 +1,157 @@
+package simulated_time
+
+import (
+	"context"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/metamogul/timing"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArrivals(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	action := timing.ActionFunc(func(timing.ActionContext) {})
+
+	tests := []struct {
+		name      string
+		generator func(source rand.Source, until *time.Time) EventGenerator
+		meanGap   time.Duration
+		minGap    time.Duration
+		maxGap    time.Duration
+	}{
+		{
+			name: "Poisson",
+			generator: func(source rand.Source, until *time.Time) EventGenerator {
+				return PoissonArrivals(action, now, until, time.Second, source, context.Background())
+			},
+			meanGap: time.Second,
+			maxGap:  time.Hour,
+		},
+		{
+			name: "uniform",
+			generator: func(source rand.Source, until *time.Time) EventGenerator {
+				return UniformArrivals(action, now, until, 500*time.Millisecond, 1500*time.Millisecond, source, context.Background())
+			},
+			meanGap: time.Second,
+			minGap:  500 * time.Millisecond,
+			maxGap:  1500 * time.Millisecond,
+		},
+		{
+			name: "normal",
+			generator: func(source rand.Source, until *time.Time) EventGenerator {
+				return NormalArrivals(action, now, until, time.Second, 100*time.Millisecond, source, context.Background())
+			},
+			meanGap: time.Second,
+			maxGap:  time.Hour,
+		},
+		{
+			name: "log-normal",
+			generator: func(source rand.Source, until *time.Time) EventGenerator {
+				// The mean of a log-normal distribution is median * e^(sigma²/2).
+				return LogNormalArrivals(action, now, until, 882497*time.Microsecond, 0.5, source, context.Background())
+			},
+			meanGap: time.Second,
+			maxGap:  time.Hour,
+		},
+		{
+			name: "bursty",
+			generator: func(source rand.Source, until *time.Time) EventGenerator {
+				return BurstyArrivals(action, now, until, []ArrivalPhase{
+					{MeanGap: 500 * time.Millisecond, MeanDuration: time.Minute},
+					{MeanGap: 0, MeanDuration: time.Minute},
+				}, source, context.Background())
+			},
+			meanGap: time.Second,
+			maxGap:  time.Hour,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			until := now.Add(20000 * time.Second)
+			generator := tt.generator(rand.NewPCG(1, 2), &until)
+			sameSeedGenerator := tt.generator(rand.NewPCG(1, 2), &until)
+
+			previous := now
+			arrivals := 0
+			for !generator.Finished() {
+				event := generator.Pop()
+				require.Equal(t, event.Time, sameSeedGenerator.Pop().Time)
+
+				gap := event.Time.Sub(previous)
+				require.GreaterOrEqual(t, gap, tt.minGap)
+				require.LessOrEqual(t, gap, tt.maxGap)
+				require.False(t, event.Time.After(until))
+
+				previous = event.Time
+				arrivals++
+			}
+			require.True(t, sameSeedGenerator.Finished())
+
+			require.InEpsilon(t, int(until.Sub(now)/tt.meanGap), arrivals, 0.1)
+			require.PanicsWithValue(t, ErrEventGeneratorFinished, func() { generator.Pop() })
+			require.PanicsWithValue(t, ErrEventGeneratorFinished, func() { generator.Peek() })
+
+			require.NotEqual(t, tt.generator(rand.NewPCG(1, 2), nil).Peek().Time, tt.generator(rand.NewPCG(3, 4), nil).Peek().Time)
+		})
+	}
+}
+
+func TestArrivals_cancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	generator := PoissonArrivals(timing.ActionFunc(func(timing.ActionContext) {}), time.Time{}, nil, time.Second, rand.NewPCG(1, 2), ctx)
+	generator.Pop()
+	require.False(t, generator.Finished())
+
+	cancel()
+	require.True(t, generator.Finished())
+}
+
+func TestArrivals_SerialEventScheduler(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	performed := 0
+	action := timing.ActionFunc(func(timing.ActionContext) { performed++ })
+
+	eventScheduler := NewSerialEventScheduler(now)
+	eventScheduler.AddGenerator(PoissonArrivals(action, now, ptr(now.Add(time.Hour)), time.Second, rand.NewPCG(1, 2), context.Background()))
+	eventScheduler.Forward(2 * time.Hour)
+
+	require.InEpsilon(t, 3600, performed, 0.1)
+}
+
+func TestArrivals_invalid(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	action := timing.ActionFunc(func(timing.ActionContext) {})
+	source := rand.NewPCG(1, 2)
+	ctx := context.Background()
+
+	require.PanicsWithValue(t, "Action can't be nil", func() { PoissonArrivals(nil, now, nil, time.Second, source, ctx) })
+	require.PanicsWithValue(t, "until must be after from", func() { PoissonArrivals(action, now, &now, time.Second, source, ctx) })
+	require.PanicsWithValue(t, "mean must be greater than zero", func() { PoissonArrivals(action, now, nil, 0, source, ctx) })
+	require.PanicsWithValue(t, "low must not be negative nor greater than high", func() { UniformArrivals(action, now, nil, 2, 1, source, ctx) })
+	require.PanicsWithValue(t, "high must be greater than zero", func() { UniformArrivals(action, now, nil, 0, 0, source, ctx) })
+	require.PanicsWithValue(t, "mean and stddev can't be negative", func() { NormalArrivals(action, now, nil, -1, 0, source, ctx) })
+	require.PanicsWithValue(t, "mean must be greater than zero", func() { NormalArrivals(action, now, nil, 0, 0, source, ctx) })
+	require.PanicsWithValue(t, "median must be greater than zero", func() { LogNormalArrivals(action, now, nil, 0, 1, source, ctx) })
+	require.PanicsWithValue(t, "sigma can't be negative", func() { LogNormalArrivals(action, now, nil, 1, -1, source, ctx) })
+	require.PanicsWithValue(t, "phases can't be empty", func() { BurstyArrivals(action, now, nil, nil, source, ctx) })
+	require.PanicsWithValue(t, "phases need a positive MeanDuration and a MeanGap that isn't negative", func() {
+		BurstyArrivals(action, now, nil, []ArrivalPhase{{MeanGap: time.Second}}, source, ctx)
+	})
+	require.PanicsWithValue(t, "at least one phase needs arrivals", func() {
+		BurstyArrivals(action, now, nil, []ArrivalPhase{{MeanDuration: time.Second}}, source, ctx)
+	})
+}
@@ -0,0 +1,54 @@
+package simulated_time
+
+import (
+	"github.com/metamogul/timing"
+)
+
+// WithCalendar returns a generator with the events of generator that fall on
+// times the calendar is closed either dropped or moved to its next opening,
+// depending on policy.
+func WithCalendar(generator EventGenerator, calendar *timing.Calendar, policy timing.CalendarPolicy) EventGenerator {
+	if calendar == nil {
+		panic("calendar can't be nil")
+	}
+
+	switch policy {
+	case timing.SkipClosed:
+		return Filter(generator, func(event Event) bool {
+			return calendar.IsOpen(event.Time)
+		})
+	case timing.ShiftToNextOpen:
+		return &calendarShiftEventGenerator{
+			EventGenerator: generator,
+			calendar:       calendar,
+		}
+	default:
+		panic("unknown calendar policy")
+	}
+}
+
+type calendarShiftEventGenerator struct {
+	EventGenerator
+	calendar *timing.Calendar
+}
+
+func (c *calendarShiftEventGenerator) Pop() *Event {
+	event := *c.EventGenerator.Pop()
+	event.Time = c.calendar.ShiftToOpen(event.Time)
+
+	return &event
+}
+
+func (c *calendarShiftEventGenerator) Peek() Event {
+	event := c.EventGenerator.Peek()
+	event.Time = c.calendar.ShiftToOpen(event.Time)
+
+	return event
+}
+
+func (c *calendarShiftEventGenerator) Clone() EventGenerator {
+	return &calendarShiftEventGenerator{
+		EventGenerator: Clone(c.EventGenerator),
+		calendar:       c.calendar,
+	}
+}
@@ -0,0 +1,115 @@
+package simulated_time
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/metamogul/timing"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithCalendar(t *testing.T) {
+	t.Parallel()
+
+	// Thursday, 2024-12-26
+	now := time.Date(2024, 12, 26, 12, 0, 0, 0, time.UTC)
+	action := timing.ActionFunc(func(timing.ActionContext) {})
+
+	calendar := timing.NewCalendar(nil)
+	calendar.CloseWeekdays(time.Saturday, time.Sunday)
+
+	monday := time.Date(2024, 12, 30, 12, 0, 0, 0, time.UTC).Sub(now)
+
+	tests := []struct {
+		name   string
+		policy timing.CalendarPolicy
+		want   []time.Duration
+	}{
+		{
+			name:   "skip",
+			policy: timing.SkipClosed,
+			want:   []time.Duration{24 * time.Hour, 4 * 24 * time.Hour, 5 * 24 * time.Hour},
+		},
+		{
+			name:   "shift",
+			policy: timing.ShiftToNextOpen,
+			want:   []time.Duration{24 * time.Hour, monday, monday, monday, 5 * 24 * time.Hour},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			generator := WithCalendar(Take(Every(action, now, 24*time.Hour, context.Background()), 5), calendar, tt.policy)
+
+			require.Equal(t, tt.want, drain(t, generator, now))
+		})
+	}
+}
+
+func TestWithCalendar_invalid(t *testing.T) {
+	t.Parallel()
+
+	generator := Once(timing.ActionFunc(func(timing.ActionContext) {}), time.Time{}, context.Background())
+
+	require.PanicsWithValue(t, "calendar can't be nil", func() { WithCalendar(generator, nil, timing.SkipClosed) })
+	require.PanicsWithValue(t, "unknown calendar policy", func() { WithCalendar(generator, timing.NewCalendar(nil), -1) })
+}
+
+func TestCalendarEventScheduler_SerialEventScheduler(t *testing.T) {
+	t.Parallel()
+
+	// Thursday, 2024-12-26
+	now := time.Date(2024, 12, 26, 12, 0, 0, 0, time.UTC)
+
+	calendar := timing.NewCalendar(nil)
+	calendar.CloseWeekdays(time.Saturday, time.Sunday)
+
+	tests := []struct {
+		name   string
+		policy timing.CalendarPolicy
+		want   []time.Time
+	}{
+		{
+			name:   "skip",
+			policy: timing.SkipClosed,
+			want: []time.Time{
+				time.Date(2024, 12, 27, 12, 0, 0, 0, time.UTC),
+				time.Date(2024, 12, 30, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:   "shift",
+			policy: timing.ShiftToNextOpen,
+			want: []time.Time{
+				time.Date(2024, 12, 27, 12, 0, 0, 0, time.UTC),
+				time.Date(2024, 12, 30, 12, 0, 0, 0, time.UTC),
+				time.Date(2024, 12, 30, 12, 0, 0, 0, time.UTC),
+				time.Date(2024, 12, 30, 12, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			serialEventScheduler := NewSerialEventScheduler(now)
+			eventScheduler := timing.CalendarEventScheduler{
+				EventScheduler: serialEventScheduler,
+				Calendar:       calendar,
+				Policy:         tt.policy,
+			}
+
+			performed := make([]time.Time, 0)
+			action := timing.ActionFunc(func(ctx timing.ActionContext) {
+				performed = append(performed, ctx.Clock().Now())
+			})
+
+			eventScheduler.PerformRepeatedly(action, ptr(now.Add(5*24*time.Hour)), 24*time.Hour, context.Background())
+			serialEventScheduler.Forward(6 * 24 * time.Hour)
+
+			require.Equal(t, tt.want, performed)
+		})
+	}
+}
@@ -0,0 +1,319 @@
+package simulated_time
+
+import (
+	"context"
+	"time"
+
+	"github.com/metamogul/timing"
+)
+
+// Once returns a generator with a single event at the given time.
+func Once(action timing.Action, at time.Time, ctx context.Context) EventGenerator {
+	return newSingleEventGenerator(action, at, ctx)
+}
+
+// Every returns an endless generator with an event every interval, the first
+// one interval after from. Take or Until bound it.
+func Every(action timing.Action, from time.Time, interval time.Duration, ctx context.Context) EventGenerator {
+	if interval <= 0 {
+		panic("interval must be greater than zero")
+	}
+
+	return newPeriodicEventGenerator(action, from, nil, interval, ctx)
+}
+
+// Merge combines generators into one that yields all of their events in the
+// order of their times.
+func Merge(generators ...EventGenerator) EventGenerator {
+	return newEventCombinator(generators...)
+}
+
+type takeEventGenerator struct {
+	EventGenerator
+	remaining int
+}
+
+// Take returns a generator with at most the first n events of generator.
+func Take(generator EventGenerator, n int) EventGenerator {
+	if n < 0 {
+		panic("n can't be negative")
+	}
+
+	return &takeEventGenerator{
+		EventGenerator: generator,
+		remaining:      n,
+	}
+}
+
+func (t *takeEventGenerator) Pop() *Event {
+	if t.Finished() {
+		panic(ErrEventGeneratorFinished)
+	}
+
+	t.remaining--
+
+	return t.EventGenerator.Pop()
+}
+
+func (t *takeEventGenerator) Peek() Event {
+	if t.Finished() {
+		panic(ErrEventGeneratorFinished)
+	}
+
+	return t.EventGenerator.Peek()
+}
+
+func (t *takeEventGenerator) Finished() bool {
+	return t.remaining == 0 || t.EventGenerator.Finished()
+}
+
+func (t *takeEventGenerator) Clone() EventGenerator {
+	return &takeEventGenerator{
+		EventGenerator: Clone(t.EventGenerator),
+		remaining:      t.remaining,
+	}
+}
+
+type untilEventGenerator struct {
+	EventGenerator
+	until time.Time
+}
+
+// Until returns a generator with the events of generator up to and including
+// the given time.
+func Until(generator EventGenerator, until time.Time) EventGenerator {
+	return &untilEventGenerator{
+		EventGenerator: generator,
+		until:          until,
+	}
+}
+
+func (u *untilEventGenerator) Pop() *Event {
+	if u.Finished() {
+		panic(ErrEventGeneratorFinished)
+	}
+
+	return u.EventGenerator.Pop()
+}
+
+func (u *untilEventGenerator) Peek() Event {
+	if u.Finished() {
+		panic(ErrEventGeneratorFinished)
+	}
+
+	return u.EventGenerator.Peek()
+}
+
+func (u *untilEventGenerator) Finished() bool {
+	return u.EventGenerator.Finished() || u.EventGenerator.Peek().After(u.until)
+}
+
+func (u *untilEventGenerator) Clone() EventGenerator {
+	return &untilEventGenerator{
+		EventGenerator: Clone(u.EventGenerator),
+		until:          u.until,
+	}
+}
+
+type skipEventGenerator struct {
+	EventGenerator
+	remaining int
+}
+
+// Skip returns a generator without the first n events of generator.
+func Skip(generator EventGenerator, n int) EventGenerator {
+	if n < 0 {
+		panic("n can't be negative")
+	}
+
+	return &skipEventGenerator{
+		EventGenerator: generator,
+		remaining:      n,
+	}
+}
+
+func (s *skipEventGenerator) Pop() *Event {
+	if s.Finished() {
+		panic(ErrEventGeneratorFinished)
+	}
+
+	return s.EventGenerator.Pop()
+}
+
+func (s *skipEventGenerator) Peek() Event {
+	if s.Finished() {
+		panic(ErrEventGeneratorFinished)
+	}
+
+	return s.EventGenerator.Peek()
+}
+
+func (s *skipEventGenerator) Finished() bool {
+	for ; s.remaining > 0 && !s.EventGenerator.Finished(); s.remaining-- {
+		s.EventGenerator.Pop()
+	}
+
+	return s.EventGenerator.Finished()
+}
+
+func (s *skipEventGenerator) Clone() EventGenerator {
+	return &skipEventGenerator{
+		EventGenerator: Clone(s.EventGenerator),
+		remaining:      s.remaining,
+	}
+}
+
+type filterEventGenerator struct {
+	EventGenerator
+	keep     func(Event) bool
+	nextKept bool
+}
+
+// Filter returns a generator with only those events of generator that keep
+// returns true for. Filtering an endless generator down to nothing never
+// finishes. Clones share keep, so it should be stateless for Preview and the
+// like to work.
+func Filter(generator EventGenerator, keep func(Event) bool) EventGenerator {
+	if keep == nil {
+		panic("keep can't be nil")
+	}
+
+	return &filterEventGenerator{
+		EventGenerator: generator,
+		keep:           keep,
+	}
+}
+
+func (f *filterEventGenerator) Pop() *Event {
+	if f.Finished() {
+		panic(ErrEventGeneratorFinished)
+	}
+
+	f.nextKept = false
+
+	return f.EventGenerator.Pop()
+}
+
+func (f *filterEventGenerator) Peek() Event {
+	if f.Finished() {
+		panic(ErrEventGeneratorFinished)
+	}
+
+	return f.EventGenerator.Peek()
+}
+
+// Finished drops events until the next one is kept. keep is called exactly
+// once per event.
+func (f *filterEventGenerator) Finished() bool {
+	for !f.EventGenerator.Finished() {
+		if f.nextKept || f.keep(f.EventGenerator.Peek()) {
+			f.nextKept = true
+			return false
+		}
+
+		f.EventGenerator.Pop()
+	}
+
+	return true
+}
+
+func (f *filterEventGenerator) Clone() EventGenerator {
+	return &filterEventGenerator{
+		EventGenerator: Clone(f.EventGenerator),
+		keep:           f.keep,
+		nextKept:       f.nextKept,
+	}
+}
+
+type shiftEventGenerator struct {
+	EventGenerator
+	offset time.Duration
+}
+
+// Shift returns a generator with the events of generator moved later by
+// offset. A negative offset could move events before the scheduler's current
+// time, so it isn't allowed.
+func Shift(generator EventGenerator, offset time.Duration) EventGenerator {
+	if offset < 0 {
+		panic("offset can't be negative")
+	}
+
+	return &shiftEventGenerator{
+		EventGenerator: generator,
+		offset:         offset,
+	}
+}
+
+func (s *shiftEventGenerator) Pop() *Event {
+	event := *s.EventGenerator.Pop()
+	event.Time = event.Time.Add(s.offset)
+
+	return &event
+}
+
+func (s *shiftEventGenerator) Peek() Event {
+	event := s.EventGenerator.Peek()
+	event.Time = event.Time.Add(s.offset)
+
+	return event
+}
+
+func (s *shiftEventGenerator) Clone() EventGenerator {
+	return &shiftEventGenerator{
+		EventGenerator: Clone(s.EventGenerator),
+		offset:         s.offset,
+	}
+}
+
+type mapActionEventGenerator struct {
+	EventGenerator
+	mapAction func(timing.Action) timing.Action
+
+	// next caches the mapped next event, so that mapAction is called once
+	// per event no matter how often it is peeked at.
+	next *Event
+}
+
+// MapAction returns a generator whose events perform mapAction applied to the
+// actions of generator's events, e.g. to wrap them in a timing.ActionMiddleware.
+func MapAction(generator EventGenerator, mapAction func(timing.Action) timing.Action) EventGenerator {
+	if mapAction == nil {
+		panic("mapAction can't be nil")
+	}
+
+	return &mapActionEventGenerator{
+		EventGenerator: generator,
+		mapAction:      mapAction,
+	}
+}
+
+func (m *mapActionEventGenerator) Pop() *Event {
+	m.Peek()
+	m.EventGenerator.Pop()
+
+	defer func() { m.next = nil }()
+
+	return m.next
+}
+
+func (m *mapActionEventGenerator) Peek() Event {
+	if m.Finished() {
+		panic(ErrEventGeneratorFinished)
+	}
+
+	if m.next == nil {
+		event := m.EventGenerator.Peek()
+		event.Action = m.mapAction(event.Action)
+		m.next = &event
+	}
+
+	return *m.next
+}
+
+func (m *mapActionEventGenerator) Clone() EventGenerator {
+	return &mapActionEventGenerator{
+		EventGenerator: Clone(m.EventGenerator),
+		mapAction:      m.mapAction,
+		next:           m.next,
+	}
+}
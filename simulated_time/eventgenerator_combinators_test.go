@@ -0,0 +1,182 @@
+package simulated_time
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/metamogul/timing"
+	"github.com/stretchr/testify/require"
+)
+
+// drain pops all events of generator and returns their times relative to
+// from.
+func drain(t *testing.T, generator EventGenerator, from time.Time) []time.Duration {
+	t.Helper()
+
+	offsets := make([]time.Duration, 0)
+	for !generator.Finished() {
+		require.Equal(t, generator.Peek().Time, generator.Peek().Time)
+		offsets = append(offsets, generator.Pop().Time.Sub(from))
+	}
+
+	require.PanicsWithValue(t, ErrEventGeneratorFinished, func() { generator.Pop() })
+	require.PanicsWithValue(t, ErrEventGeneratorFinished, func() { generator.Peek() })
+
+	return offsets
+}
+
+func TestCombinators(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	action := timing.ActionFunc(func(timing.ActionContext) {})
+
+	every := func() EventGenerator {
+		return Every(action, now, time.Minute, context.Background())
+	}
+
+	tests := []struct {
+		name      string
+		generator func() EventGenerator
+		want      []time.Duration
+	}{
+		{
+			name:      "Once",
+			generator: func() EventGenerator { return Once(action, now.Add(time.Hour), context.Background()) },
+			want:      []time.Duration{time.Hour},
+		},
+		{
+			name:      "Take",
+			generator: func() EventGenerator { return Take(every(), 3) },
+			want:      []time.Duration{time.Minute, 2 * time.Minute, 3 * time.Minute},
+		},
+		{
+			name:      "Take none",
+			generator: func() EventGenerator { return Take(every(), 0) },
+			want:      []time.Duration{},
+		},
+		{
+			name:      "Until",
+			generator: func() EventGenerator { return Until(every(), now.Add(3*time.Minute)) },
+			want:      []time.Duration{time.Minute, 2 * time.Minute, 3 * time.Minute},
+		},
+		{
+			name:      "Skip",
+			generator: func() EventGenerator { return Take(Skip(every(), 2), 2) },
+			want:      []time.Duration{3 * time.Minute, 4 * time.Minute},
+		},
+		{
+			name: "Filter",
+			generator: func() EventGenerator {
+				return Until(Filter(every(), func(event Event) bool { return event.Minute()%2 == 0 }), now.Add(6*time.Minute))
+			},
+			want: []time.Duration{2 * time.Minute, 4 * time.Minute, 6 * time.Minute},
+		},
+		{
+			name:      "Shift",
+			generator: func() EventGenerator { return Shift(Take(every(), 2), 30*time.Second) },
+			want:      []time.Duration{90 * time.Second, 150 * time.Second},
+		},
+		{
+			name: "Merge",
+			generator: func() EventGenerator {
+				return Merge(
+					Take(Every(action, now, time.Hour, context.Background()), 2),
+					Take(Shift(every(), 30*time.Minute), 2),
+					Once(action, now, context.Background()),
+				)
+			},
+			want: []time.Duration{0, 31 * time.Minute, 32 * time.Minute, time.Hour, 2 * time.Hour},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, drain(t, tt.generator(), now))
+		})
+	}
+}
+
+func TestMapAction(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	performed := make([]string, 0)
+	named := func(name string) timing.Action {
+		return timing.ActionFunc(func(timing.ActionContext) { performed = append(performed, name) })
+	}
+
+	mapped := 0
+	generator := MapAction(Take(Every(named("inner"), now, time.Minute, context.Background()), 2), func(action timing.Action) timing.Action {
+		mapped++
+		return timing.ActionFunc(func(ctx timing.ActionContext) {
+			named("outer").Perform(ctx)
+			action.Perform(ctx)
+		})
+	})
+
+	generator.Peek()
+	generator.Peek()
+	generator.Pop().Perform(nil)
+	require.Equal(t, 1, mapped)
+
+	require.Equal(t, now.Add(2*time.Minute), generator.Pop().Time)
+	require.Equal(t, 2, mapped)
+	require.True(t, generator.Finished())
+	require.Equal(t, []string{"outer", "inner"}, performed)
+
+	require.PanicsWithValue(t, "mapAction can't be nil", func() { MapAction(generator, nil) })
+}
+
+func TestCombinators_cancelled(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	action := timing.ActionFunc(func(timing.ActionContext) {})
+	generator := MapAction(Shift(Filter(Every(action, now, time.Minute, ctx), func(Event) bool { return true }), time.Second), func(action timing.Action) timing.Action {
+		return action
+	})
+
+	require.False(t, generator.Finished())
+	generator.Peek()
+
+	cancel()
+	require.True(t, generator.Finished())
+	require.PanicsWithValue(t, ErrEventGeneratorFinished, func() { generator.Peek() })
+}
+
+func TestCombinators_invalid(t *testing.T) {
+	t.Parallel()
+
+	action := timing.ActionFunc(func(timing.ActionContext) {})
+	generator := Once(action, time.Time{}, context.Background())
+
+	require.PanicsWithValue(t, "interval must be greater than zero", func() { Every(action, time.Time{}, 0, context.Background()) })
+	require.PanicsWithValue(t, "interval must be greater than zero", func() { Every(action, time.Time{}, -time.Second, context.Background()) })
+	require.PanicsWithValue(t, "n can't be negative", func() { Take(generator, -1) })
+	require.PanicsWithValue(t, "n can't be negative", func() { Skip(generator, -1) })
+	require.PanicsWithValue(t, "keep can't be nil", func() { Filter(generator, nil) })
+	require.PanicsWithValue(t, "offset can't be negative", func() { Shift(generator, -time.Second) })
+}
+
+func TestCombinators_SerialEventScheduler(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	performedAt := make([]time.Time, 0)
+	action := timing.ActionFunc(func(ctx timing.ActionContext) {
+		performedAt = append(performedAt, ctx.Clock().Now())
+	})
+
+	eventScheduler := NewSerialEventScheduler(now)
+	eventScheduler.AddGenerator(Take(Skip(Every(action, now, time.Hour, context.Background()), 1), 2))
+	eventScheduler.Forward(24 * time.Hour)
+
+	require.Equal(t, []time.Time{now.Add(2 * time.Hour), now.Add(3 * time.Hour)}, performedAt)
+}
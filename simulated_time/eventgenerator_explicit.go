@@ -0,0 +1,70 @@
+package simulated_time
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/metamogul/timing"
+)
+
+// TimedAction is an action to be performed at a given time.
+type TimedAction struct {
+	Time   time.Time
+	Action timing.Action
+}
+
+type explicitEventGenerator struct {
+	events []*Event
+	ctx    context.Context
+}
+
+// Explicit returns a generator with an event for each of timedActions, which
+// don't need to be sorted. Actions sharing a time keep their given order.
+func Explicit(timedActions []TimedAction, ctx context.Context) EventGenerator {
+	events := make([]*Event, 0, len(timedActions))
+	for _, timedAction := range timedActions {
+		events = append(events, NewEvent(timedAction.Action, timedAction.Time, ctx))
+	}
+
+	slices.SortStableFunc(events, func(a, b *Event) int {
+		return a.Time.Compare(b.Time)
+	})
+
+	for i, event := range events {
+		event.Occurrence = i + 1
+	}
+
+	return &explicitEventGenerator{
+		events: events,
+		ctx:    ctx,
+	}
+}
+
+func (e *explicitEventGenerator) Pop() *Event {
+	if e.Finished() {
+		panic(ErrEventGeneratorFinished)
+	}
+
+	nextEvent := e.events[0]
+	e.events = e.events[1:]
+
+	return nextEvent
+}
+
+func (e *explicitEventGenerator) Peek() Event {
+	if e.Finished() {
+		panic(ErrEventGeneratorFinished)
+	}
+
+	return *e.events[0]
+}
+
+func (e *explicitEventGenerator) Finished() bool {
+	return len(e.events) == 0 || e.ctx.Err() != nil
+}
+
+func (e *explicitEventGenerator) Clone() EventGenerator {
+	clone := *e
+	return &clone
+}
@@ -0,0 +1,71 @@
+package simulated_time
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/metamogul/timing"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplicit(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	action1 := timing.NewMockAction(t)
+	action2 := timing.NewMockAction(t)
+	action3 := timing.NewMockAction(t)
+
+	generator := Explicit([]TimedAction{
+		{Time: now.Add(time.Hour), Action: action1},
+		{Time: now, Action: action2},
+		{Time: now.Add(time.Hour), Action: action3},
+	}, context.Background())
+
+	require.False(t, generator.Finished())
+	require.Same(t, action2, generator.Peek().Action)
+	require.Same(t, action2, generator.Pop().Action)
+	require.Same(t, action1, generator.Pop().Action)
+	require.Same(t, action3, generator.Pop().Action)
+	require.True(t, generator.Finished())
+	require.PanicsWithValue(t, ErrEventGeneratorFinished, func() { generator.Pop() })
+	require.PanicsWithValue(t, ErrEventGeneratorFinished, func() { generator.Peek() })
+
+	require.True(t, Explicit(nil, context.Background()).Finished())
+}
+
+func TestExplicit_cancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	generator := Explicit([]TimedAction{{Time: time.Time{}, Action: timing.NewMockAction(t)}}, ctx)
+	require.False(t, generator.Finished())
+
+	cancel()
+	require.True(t, generator.Finished())
+}
+
+func TestExplicit_SerialEventScheduler(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	performed := make([]string, 0)
+	named := func(name string) timing.Action {
+		return timing.ActionFunc(func(timing.ActionContext) { performed = append(performed, name) })
+	}
+
+	eventScheduler := NewSerialEventScheduler(now)
+	eventScheduler.AddGenerator(Explicit([]TimedAction{
+		{Time: now.Add(3 * time.Minute), Action: named("explicit 3m")},
+		{Time: now.Add(30 * time.Second), Action: named("explicit 30s")},
+	}, context.Background()))
+	eventScheduler.AddGenerator(Take(Every(named("every minute"), now, time.Minute, context.Background()), 2))
+
+	eventScheduler.Forward(time.Hour)
+
+	require.Equal(t, []string{"explicit 30s", "every minute", "every minute", "explicit 3m"}, performed)
+}
@@ -0,0 +1,100 @@
+package simulated_time
+
+import (
+	"context"
+
+	"github.com/metamogul/timing"
+	"github.com/metamogul/timing/internal/instrument"
+)
+
+// instrumentedEventGenerator reports to metrics when the generator it wraps
+// finishes because its context was cancelled rather than by running out of
+// events, and calls the respective hooks either way.
+type instrumentedEventGenerator struct {
+	EventGenerator
+
+	metrics  timing.Metrics
+	hooks    *timing.Hooks
+	job      string
+	ctx      context.Context
+	finished bool
+
+	// metadata is that of the next event, or of the last one once the
+	// generator ran out of events.
+	metadata timing.EventMetadata
+}
+
+func instrumentEventGenerator(generator EventGenerator, metrics timing.Metrics, hooks *timing.Hooks) EventGenerator {
+	if metrics == nil && hooks == nil {
+		return generator
+	}
+
+	nextEvent, ok := peekGenerator(generator)
+	if !ok {
+		return generator
+	}
+
+	ctx := nextEvent.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	job := timing.JobNameFrom(ctx)
+	metadata := timing.EventMetadata{ScheduledTime: nextEvent.Time, JobName: job, Occurrence: nextEvent.Occurrence}
+
+	if metrics != nil {
+		metrics.EventScheduled(job)
+	}
+	instrument.Scheduled(hooks, metadata)
+
+	return &instrumentedEventGenerator{
+		EventGenerator: generator,
+		metrics:        metrics,
+		hooks:          hooks,
+		job:            job,
+		ctx:            ctx,
+		metadata:       metadata,
+	}
+}
+
+func (i *instrumentedEventGenerator) Pop() *Event {
+	event := i.EventGenerator.Pop()
+
+	i.metadata = timing.EventMetadata{ScheduledTime: event.Time, JobName: i.job, Occurrence: event.Occurrence}
+	if !i.Finished() {
+		if next, ok := peekGenerator(i.EventGenerator); ok {
+			i.metadata.ScheduledTime, i.metadata.Occurrence = next.Time, next.Occurrence
+		}
+	}
+
+	return event
+}
+
+func (i *instrumentedEventGenerator) Finished() bool {
+	if i.finished {
+		return true
+	}
+
+	i.finished = i.EventGenerator.Finished()
+	if !i.finished {
+		return false
+	}
+
+	if i.ctx.Err() == nil {
+		instrument.GeneratorFinished(i.hooks, i.metadata)
+		return true
+	}
+
+	if i.metrics != nil {
+		i.metrics.EventCancelled(i.job)
+	}
+	instrument.Cancelled(i.hooks, i.metadata)
+
+	return true
+}
+
+// Clone leaves out the instrumentation, so that looking ahead on a clone isn't
+// reported to metrics or hooks.
+func (i *instrumentedEventGenerator) Clone() EventGenerator {
+	return Clone(i.EventGenerator)
+}
@@ -0,0 +1,80 @@
+package simulated_time
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/metamogul/timing"
+	"github.com/metamogul/timing/internal/recorder"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_instrumentEventGenerator(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no metrics", func(t *testing.T) {
+		t.Parallel()
+
+		generator := newSingleEventGenerator(timing.NewMockAction(t), time.Time{}, context.Background())
+		require.Same(t, generator, instrumentEventGenerator(generator, nil, nil))
+	})
+
+	t.Run("generator finished", func(t *testing.T) {
+		t.Parallel()
+
+		metrics := recorder.NewMetrics()
+
+		generator := newSingleEventGenerator(timing.NewMockAction(t), time.Time{}, context.Background())
+		_ = generator.Pop()
+
+		require.Same(t, generator, instrumentEventGenerator(generator, metrics, nil))
+		require.Empty(t, metrics.Scheduled)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		metrics := recorder.NewMetrics()
+		ctx := timing.WithJobName(context.Background(), "job")
+
+		instrumented := instrumentEventGenerator(newSingleEventGenerator(timing.NewMockAction(t), time.Time{}, ctx), metrics, nil)
+		require.IsType(t, &instrumentedEventGenerator{}, instrumented)
+		require.Equal(t, map[string]int{"job": 1}, metrics.Scheduled)
+	})
+}
+
+func Test_instrumentedEventGenerator_Finished(t *testing.T) {
+	t.Parallel()
+
+	t.Run("exhausted", func(t *testing.T) {
+		t.Parallel()
+
+		metrics := recorder.NewMetrics()
+		ctx, cancel := context.WithCancel(context.Background())
+
+		generator := instrumentEventGenerator(newSingleEventGenerator(timing.NewMockAction(t), time.Time{}, ctx), metrics, nil)
+		require.False(t, generator.Finished())
+
+		_ = generator.Pop()
+		require.True(t, generator.Finished())
+
+		cancel()
+		require.True(t, generator.Finished())
+		require.Empty(t, metrics.Cancelled)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		metrics := recorder.NewMetrics()
+		ctx, cancel := context.WithCancel(timing.WithJobName(context.Background(), "job"))
+
+		generator := instrumentEventGenerator(newPeriodicEventGenerator(timing.NewMockAction(t), time.Time{}, nil, time.Second, ctx), metrics, nil)
+
+		cancel()
+		require.True(t, generator.Finished())
+		require.True(t, generator.Finished())
+		require.Equal(t, map[string]int{"job": 1}, metrics.Cancelled)
+	})
+}
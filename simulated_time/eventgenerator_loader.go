@@ -0,0 +1,162 @@
+package simulated_time
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/metamogul/timing"
+)
+
+var (
+	ErrEventsOutOfOrder = errors.New("events out of order")
+	ErrDuplicateEvent   = errors.New("duplicate event")
+)
+
+// MaxJSONLLineSize is the longest line LoadJSONL accepts.
+const MaxJSONLLineSize = 16 << 20
+
+// recordedEvent is a line of an events file.
+type recordedEvent struct {
+	Time   time.Time       `json:"time"`
+	Action string          `json:"action"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// LoadCSV reads events from CSV records of an RFC 3339 time, an action name
+// and, optionally, the action's JSON parameters. A first record starting with
+// "time" is taken as a header. Actions are resolved through registry, or
+// through timing.DefaultActionRegistry if registry is nil.
+//
+// The events must be sorted by time, and no record may appear twice.
+func LoadCSV(r io.Reader, registry *timing.ActionRegistry, ctx context.Context) (EventGenerator, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	records := make([]recordedEvent, 0)
+	for line := 1; ; line++ {
+		fields, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if line == 1 && strings.EqualFold(fields[0], "time") {
+			continue
+		}
+
+		if len(fields) < 2 || len(fields) > 3 {
+			return nil, fmt.Errorf("line %d: expected time, action and optional params, got %d fields", line, len(fields))
+		}
+
+		eventTime, err := time.Parse(time.RFC3339Nano, fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		record := recordedEvent{Time: eventTime, Action: fields[1]}
+		if len(fields) == 3 && fields[2] != "" {
+			record.Params = json.RawMessage(fields[2])
+		}
+
+		records = append(records, record)
+	}
+
+	return buildLoaded(records, registry, ctx)
+}
+
+// LoadJSONL reads events from JSON lines with the fields time, action and,
+// optionally, params. Empty lines are skipped, and lines may be up to
+// MaxJSONLLineSize bytes long. Otherwise it works like LoadCSV.
+func LoadJSONL(r io.Reader, registry *timing.ActionRegistry, ctx context.Context) (EventGenerator, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, MaxJSONLLineSize)
+
+	records := make([]recordedEvent, 0)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		var record recordedEvent
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		records = append(records, record)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return buildLoaded(records, registry, ctx)
+}
+
+// LoadFile loads events from a .csv or .jsonl file.
+func LoadFile(path string, registry *timing.ActionRegistry, ctx context.Context) (EventGenerator, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return LoadCSV(file, registry, ctx)
+	case ".jsonl":
+		return LoadJSONL(file, registry, ctx)
+	default:
+		return nil, fmt.Errorf("unsupported events file %s", path)
+	}
+}
+
+// buildLoaded validates records and resolves their actions. Errors count the
+// events from 1.
+func buildLoaded(records []recordedEvent, registry *timing.ActionRegistry, ctx context.Context) (EventGenerator, error) {
+	if registry == nil {
+		registry = timing.DefaultActionRegistry
+	}
+
+	// Times are compared as instants, whatever their location.
+	type eventKey struct {
+		time   int64
+		action string
+		params string
+	}
+
+	seen := make(map[eventKey]struct{}, len(records))
+	timedActions := make([]TimedAction, 0, len(records))
+
+	for i, record := range records {
+		if i > 0 && record.Time.Before(records[i-1].Time) {
+			return nil, fmt.Errorf("event %d at %s: %w", i+1, record.Time.Format(time.RFC3339Nano), ErrEventsOutOfOrder)
+		}
+
+		key := eventKey{record.Time.UnixNano(), record.Action, string(record.Params)}
+		if _, exists := seen[key]; exists {
+			return nil, fmt.Errorf("event %d, %s at %s: %w", i+1, record.Action, record.Time.Format(time.RFC3339Nano), ErrDuplicateEvent)
+		}
+		seen[key] = struct{}{}
+
+		action, err := registry.New(record.Action, record.Params)
+		if err != nil {
+			return nil, fmt.Errorf("event %d: %w", i+1, err)
+		}
+
+		timedActions = append(timedActions, TimedAction{Time: record.Time, Action: action})
+	}
+
+	return Explicit(timedActions, ctx), nil
+}
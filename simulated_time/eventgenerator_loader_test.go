@@ -0,0 +1,169 @@
+package simulated_time
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/metamogul/timing"
+	"github.com/stretchr/testify/require"
+)
+
+func newLoaderRegistry() *timing.ActionRegistry {
+	registry := timing.NewActionRegistry()
+	registry.Register("noop", func(json.RawMessage) (timing.Action, error) {
+		return timing.ActionFunc(func(timing.ActionContext) {}), nil
+	})
+
+	return registry
+}
+
+func loadedEvents(t *testing.T, generator EventGenerator) []recordedEvent {
+	t.Helper()
+
+	events := make([]recordedEvent, 0)
+	for !generator.Finished() {
+		event := generator.Pop()
+		namedAction, ok := event.Action.(*timing.NamedAction)
+		require.True(t, ok)
+
+		events = append(events, recordedEvent{Time: event.Time, Action: namedAction.Name, Params: namedAction.Params})
+	}
+
+	return events
+}
+
+func TestLoadCSV(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		input   string
+		want    []recordedEvent
+		wantErr error
+		errText string
+	}{
+		{
+			name:  "with header and params",
+			input: "time,action,params\n2024-01-01T12:00:00Z,noop,\n2024-01-01T12:00:01.5Z,noop,\"{\"\"n\"\":1}\"\n",
+			want: []recordedEvent{
+				{Time: now, Action: "noop"},
+				{Time: now.Add(1500 * time.Millisecond), Action: "noop", Params: json.RawMessage(`{"n":1}`)},
+			},
+		},
+		{
+			name:  "without header",
+			input: "2024-01-01T12:00:00Z,noop\n2024-01-01T12:00:00Z,noop,{}\n",
+			want: []recordedEvent{
+				{Time: now, Action: "noop"},
+				{Time: now, Action: "noop", Params: json.RawMessage(`{}`)},
+			},
+		},
+		{
+			name:    "out of order",
+			input:   "2024-01-01T12:00:01Z,noop\n2024-01-01T12:00:00Z,noop\n",
+			wantErr: ErrEventsOutOfOrder,
+		},
+		{
+			name:    "duplicate",
+			input:   "2024-01-01T12:00:00Z,noop\n2024-01-01T12:00:00Z,noop\n",
+			wantErr: ErrDuplicateEvent,
+		},
+		{
+			name:    "duplicate in another time zone",
+			input:   "2024-01-01T12:00:00Z,noop\n2024-01-01T13:00:00+01:00,noop\n",
+			wantErr: ErrDuplicateEvent,
+		},
+		{
+			name:    "unknown action",
+			input:   "2024-01-01T12:00:00Z,unknown\n",
+			wantErr: timing.ErrActionNotRegistered,
+		},
+		{
+			name:    "invalid time",
+			input:   "yesterday,noop\n",
+			errText: "line 1",
+		},
+		{
+			name:    "missing action",
+			input:   "2024-01-01T12:00:00Z\n",
+			errText: "line 1: expected time, action and optional params, got 1 fields",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			generator, err := LoadCSV(strings.NewReader(tt.input), newLoaderRegistry(), context.Background())
+
+			switch {
+			case tt.wantErr != nil:
+				require.ErrorIs(t, err, tt.wantErr)
+			case tt.errText != "":
+				require.ErrorContains(t, err, tt.errText)
+			default:
+				require.NoError(t, err)
+				require.Equal(t, tt.want, loadedEvents(t, generator))
+			}
+		})
+	}
+}
+
+func TestLoadJSONL(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	input := `{"time":"2024-01-01T12:00:00Z","action":"noop"}
+
+{"time":"2024-01-01T13:00:00Z","action":"noop","params":{"n":2}}
+`
+	generator, err := LoadJSONL(strings.NewReader(input), newLoaderRegistry(), context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []recordedEvent{
+		{Time: now, Action: "noop"},
+		{Time: now.Add(time.Hour), Action: "noop", Params: json.RawMessage(`{"n":2}`)},
+	}, loadedEvents(t, generator))
+
+	_, err = LoadJSONL(strings.NewReader(`{"time":"2024-01-01T13:00:00Z","action":"noop"}`+"\n"+`{"time":"2024-01-01T12:00:00Z","action":"noop"}`), newLoaderRegistry(), context.Background())
+	require.ErrorIs(t, err, ErrEventsOutOfOrder)
+
+	_, err = LoadJSONL(strings.NewReader("{\n"), newLoaderRegistry(), context.Background())
+	require.ErrorContains(t, err, "line 1")
+
+	longParams := `{"padding":"` + strings.Repeat("x", 100_000) + `"}`
+	generator, err = LoadJSONL(strings.NewReader(`{"time":"2024-01-01T12:00:00Z","action":"noop","params":`+longParams+"}\n"), newLoaderRegistry(), context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []recordedEvent{{Time: now, Action: "noop", Params: json.RawMessage(longParams)}}, loadedEvents(t, generator))
+}
+
+func TestLoadFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	csvPath := filepath.Join(dir, "events.csv")
+	require.NoError(t, os.WriteFile(csvPath, []byte("2024-01-01T12:00:00Z,noop\n"), 0o600))
+	jsonlPath := filepath.Join(dir, "events.jsonl")
+	require.NoError(t, os.WriteFile(jsonlPath, []byte(`{"time":"2024-01-01T12:00:00Z","action":"noop"}`), 0o600))
+	txtPath := filepath.Join(dir, "events.txt")
+	require.NoError(t, os.WriteFile(txtPath, nil, 0o600))
+
+	for _, path := range []string{csvPath, jsonlPath} {
+		generator, err := LoadFile(path, newLoaderRegistry(), context.Background())
+		require.NoError(t, err)
+		require.Len(t, loadedEvents(t, generator), 1)
+	}
+
+	_, err := LoadFile(txtPath, newLoaderRegistry(), context.Background())
+	require.ErrorContains(t, err, "unsupported events file")
+
+	_, err = LoadFile(filepath.Join(dir, "missing.csv"), newLoaderRegistry(), context.Background())
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
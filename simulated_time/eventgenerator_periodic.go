@@ -41,6 +41,7 @@ func newPeriodicEventGenerator(
 	}
 
 	firstEvent := NewEvent(action, from.Add(interval), ctx)
+	firstEvent.Occurrence = 1
 
 	return &periodicEventGenerator{
 		action:   action,
@@ -59,9 +60,12 @@ func (p *periodicEventGenerator) Pop() *Event {
 		panic(ErrEventGeneratorFinished)
 	}
 
-	defer func() { p.currentEvent = NewEvent(p.action, p.currentEvent.Time.Add(p.interval), p.ctx) }()
+	event := p.currentEvent
 
-	return p.currentEvent
+	p.currentEvent = NewEvent(p.action, event.Time.Add(p.interval), p.ctx)
+	p.currentEvent.Occurrence = event.Occurrence + 1
+
+	return event
 }
 
 func (p *periodicEventGenerator) Peek() Event {
@@ -83,3 +87,8 @@ func (p *periodicEventGenerator) Finished() bool {
 
 	return p.currentEvent.Add(p.interval).After(*p.to)
 }
+
+func (p *periodicEventGenerator) Clone() EventGenerator {
+	clone := *p
+	return &clone
+}
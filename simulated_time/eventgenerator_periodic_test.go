@@ -98,9 +98,10 @@ func Test_newPeriodicEventGenerator(t *testing.T) {
 				to:       ptr(time.Time{}.Add(2 * time.Second)),
 				interval: time.Second,
 				currentEvent: &Event{
-					Action:  timing.NewMockAction(t),
-					Time:    time.Time{}.Add(time.Second),
-					Context: ctx,
+					Action:     timing.NewMockAction(t),
+					Time:       time.Time{}.Add(time.Second),
+					Context:    ctx,
+					Occurrence: 1,
 				},
 				ctx: ctx,
 			},
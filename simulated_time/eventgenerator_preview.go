@@ -0,0 +1,72 @@
+package simulated_time
+
+import (
+	"iter"
+	"time"
+)
+
+// Clone returns a copy of generator, see CloneableEventGenerator. It panics
+// with ErrEventGeneratorNotCloneable if generator isn't cloneable.
+func Clone(generator EventGenerator) EventGenerator {
+	cloneable, ok := generator.(CloneableEventGenerator)
+	if !ok {
+		panic(ErrEventGeneratorNotCloneable)
+	}
+
+	return cloneable.Clone()
+}
+
+// Upcoming returns the events generator has yet to yield, without consuming
+// them. They are those from the time Upcoming is called, and every iteration
+// starts over from there. Iterating an endless generator doesn't end until
+// the loop is left.
+func Upcoming(generator EventGenerator) iter.Seq[Event] {
+	snapshot := Clone(generator)
+
+	return func(yield func(Event) bool) {
+		for clone := Clone(snapshot); !clone.Finished(); {
+			if !yield(*clone.Pop()) {
+				return
+			}
+		}
+	}
+}
+
+// Preview returns up to the next n events of generator, without consuming
+// them.
+func Preview(generator EventGenerator, n int) []Event {
+	if n < 0 {
+		panic("n can't be negative")
+	}
+
+	events := make([]Event, 0, n)
+	if n == 0 {
+		return events
+	}
+
+	for event := range Upcoming(generator) {
+		events = append(events, event)
+		if len(events) == n {
+			break
+		}
+	}
+
+	return events
+}
+
+// Between returns the upcoming events of generator from from up to, but
+// excluding, to, without consuming them.
+func Between(generator EventGenerator, from, to time.Time) []Event {
+	events := make([]Event, 0)
+	for event := range Upcoming(generator) {
+		if !event.Time.Before(to) {
+			break
+		}
+
+		if !event.Time.Before(from) {
+			events = append(events, event)
+		}
+	}
+
+	return events
+}
@@ -0,0 +1,174 @@
+package simulated_time
+
+import (
+	"context"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/metamogul/timing"
+	"github.com/metamogul/timing/internal/recorder"
+	"github.com/stretchr/testify/require"
+)
+
+func eventTimes(events []Event) []time.Time {
+	times := make([]time.Time, 0, len(events))
+	for _, event := range events {
+		times = append(times, event.Time)
+	}
+
+	return times
+}
+
+func TestClone(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	action := timing.ActionFunc(func(timing.ActionContext) {})
+	ctx := context.Background()
+
+	every := func() EventGenerator {
+		return Take(Every(action, now, time.Minute, ctx), 5)
+	}
+
+	calendar := timing.NewCalendar(nil)
+	calendar.AddBlackout(now.Add(90*time.Second), now.Add(4*time.Minute))
+
+	tests := []struct {
+		name      string
+		generator func() EventGenerator
+	}{
+		{name: "single", generator: func() EventGenerator { return Once(action, now, ctx) }},
+		{name: "periodic", generator: func() EventGenerator {
+			return newPeriodicEventGenerator(action, now, ptr(now.Add(time.Hour)), time.Minute, ctx)
+		}},
+		{name: "timing wheel", generator: func() EventGenerator {
+			return newTimingWheelEventGenerator(action, now, time.Minute, ptr(now.Add(time.Hour)), time.Second, ctx)
+		}},
+		{name: "explicit", generator: func() EventGenerator {
+			return Explicit([]TimedAction{{now.Add(time.Hour), action}, {now, action}, {now.Add(time.Minute), action}}, ctx)
+		}},
+		{name: "Poisson", generator: func() EventGenerator {
+			return PoissonArrivals(action, now, ptr(now.Add(time.Minute)), time.Second, rand.NewPCG(1, 2), ctx)
+		}},
+		{name: "bursty", generator: func() EventGenerator {
+			return BurstyArrivals(action, now, ptr(now.Add(time.Hour)), []ArrivalPhase{
+				{MeanGap: time.Second, MeanDuration: time.Minute},
+				{MeanGap: 0, MeanDuration: time.Minute},
+			}, rand.NewChaCha8([32]byte{}), ctx)
+		}},
+		{name: "Merge", generator: func() EventGenerator { return Merge(every(), Shift(every(), 30*time.Second)) }},
+		{name: "Until", generator: func() EventGenerator { return Until(every(), now.Add(3*time.Minute)) }},
+		{name: "Skip", generator: func() EventGenerator { return Skip(every(), 2) }},
+		{name: "Filter", generator: func() EventGenerator {
+			return Filter(every(), func(event Event) bool { return event.Minute()%2 == 0 })
+		}},
+		{name: "MapAction", generator: func() EventGenerator {
+			return MapAction(every(), func(action timing.Action) timing.Action { return action })
+		}},
+		{name: "calendar", generator: func() EventGenerator { return WithCalendar(every(), calendar, timing.ShiftToNextOpen) }},
+		{name: "instrumented", generator: func() EventGenerator { return instrumentEventGenerator(every(), recorder.NewMetrics(), nil) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			generator := tt.generator()
+			want := drain(t, tt.generator(), now)
+
+			generator.Peek()
+			clone := Clone(generator)
+			require.Equal(t, want, drain(t, clone, now))
+			require.Equal(t, want, drain(t, generator, now))
+
+			generator = tt.generator()
+			generator.Pop()
+			require.Equal(t, want[1:], drain(t, Clone(generator), now))
+		})
+	}
+}
+
+// constantSource is a rand.Source whose state can't be copied by Clone.
+type constantSource uint64
+
+func (c constantSource) Uint64() uint64 { return uint64(c) }
+
+func TestClone_notCloneable(t *testing.T) {
+	t.Parallel()
+
+	generator := NewMockEventGenerator(t)
+	require.PanicsWithValue(t, ErrEventGeneratorNotCloneable, func() { Clone(generator) })
+	require.PanicsWithValue(t, ErrEventGeneratorNotCloneable, func() { Clone(Take(generator, 1)) })
+
+	arrivals := PoissonArrivals(timing.ActionFunc(func(timing.ActionContext) {}), time.Time{}, nil, time.Second, constantSource(4), context.Background())
+	require.PanicsWithValue(t, ErrEventGeneratorNotCloneable, func() { Clone(arrivals) })
+}
+
+func TestClone_instrumented(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	metrics := recorder.NewMetrics()
+
+	generator := instrumentEventGenerator(Every(timing.ActionFunc(func(timing.ActionContext) {}), time.Time{}, time.Minute, ctx), metrics, nil)
+	clone := Clone(generator)
+
+	cancel()
+	require.True(t, clone.Finished())
+	require.Zero(t, metrics.Count(metrics.Cancelled, ""))
+	require.True(t, generator.Finished())
+	require.Equal(t, 1, metrics.Count(metrics.Cancelled, ""))
+}
+
+func TestUpcoming(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	generator := Every(timing.ActionFunc(func(timing.ActionContext) {}), now, time.Minute, context.Background())
+
+	upcoming := Upcoming(generator)
+	generator.Pop()
+
+	for range 2 {
+		offsets := make([]time.Duration, 0)
+		for event := range upcoming {
+			offsets = append(offsets, event.Time.Sub(now))
+			if len(offsets) == 3 {
+				break
+			}
+		}
+
+		require.Equal(t, []time.Duration{time.Minute, 2 * time.Minute, 3 * time.Minute}, offsets)
+	}
+
+	require.Equal(t, now.Add(2*time.Minute), generator.Peek().Time)
+}
+
+func TestPreview(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	action := timing.ActionFunc(func(timing.ActionContext) {})
+	generator := Take(Every(action, now, time.Minute, context.Background()), 3)
+
+	require.Empty(t, Preview(generator, 0))
+	require.Equal(t, []time.Time{now.Add(time.Minute), now.Add(2 * time.Minute)}, eventTimes(Preview(generator, 2)))
+	require.Len(t, Preview(generator, 10), 3)
+	require.Equal(t, now.Add(time.Minute), generator.Peek().Time)
+
+	require.PanicsWithValue(t, "n can't be negative", func() { Preview(generator, -1) })
+}
+
+func TestBetween(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	generator := Every(timing.ActionFunc(func(timing.ActionContext) {}), now, time.Minute, context.Background())
+
+	require.Equal(t,
+		[]time.Time{now.Add(2 * time.Minute), now.Add(3 * time.Minute)},
+		eventTimes(Between(generator, now.Add(2*time.Minute), now.Add(4*time.Minute))),
+	)
+	require.Empty(t, Between(generator, now, now.Add(time.Minute)))
+	require.Equal(t, now.Add(time.Minute), generator.Peek().Time)
+}
@@ -12,8 +12,11 @@ type singleEventGenerator struct {
 }
 
 func newSingleEventGenerator(action timing.Action, time time.Time, ctx context.Context) *singleEventGenerator {
+	event := NewEvent(action, time, ctx)
+	event.Occurrence = 1
+
 	return &singleEventGenerator{
-		Event: NewEvent(action, time, ctx),
+		Event: event,
 		ctx:   ctx,
 	}
 }
@@ -39,3 +42,8 @@ func (s *singleEventGenerator) Peek() Event {
 func (s *singleEventGenerator) Finished() bool {
 	return s.Event == nil || s.ctx.Err() != nil
 }
+
+func (s *singleEventGenerator) Clone() EventGenerator {
+	clone := *s
+	return &clone
+}
@@ -44,9 +44,10 @@ func Test_newSingleEventGenerator(t *testing.T) {
 			},
 			want: &singleEventGenerator{
 				Event: &Event{
-					Action:  timing.NewMockAction(t),
-					Time:    time.Time{},
-					Context: ctx,
+					Action:     timing.NewMockAction(t),
+					Time:       time.Time{},
+					Context:    ctx,
+					Occurrence: 1,
 				},
 				ctx: ctx,
 			},
@@ -0,0 +1,102 @@
+package simulated_time
+
+import (
+	"context"
+	"time"
+
+	"github.com/metamogul/timing"
+	"github.com/metamogul/timing/internal/tick"
+)
+
+// timingWheelEventGenerator produces the same coarse event times as the
+// timing wheel engine of system.EventScheduler: every deadline is rounded up
+// to the next multiple of tick since the Unix epoch, and repeated deadlines
+// stop as soon as they are no longer before until.
+type timingWheelEventGenerator struct {
+	action   timing.Action
+	deadline time.Time
+	interval time.Duration
+	until    *time.Time
+	tick     time.Duration
+
+	done   bool
+	popped int
+
+	ctx context.Context
+}
+
+func newTimingWheelEventGenerator(
+	action timing.Action,
+	deadline time.Time,
+	interval time.Duration,
+	until *time.Time,
+	tick time.Duration,
+	ctx context.Context,
+) *timingWheelEventGenerator {
+	if action == nil {
+		panic("action can't be nil")
+	}
+
+	if interval < 0 {
+		panic("interval can't be negative")
+	}
+
+	if tick <= 0 {
+		panic("tick must be greater than zero")
+	}
+
+	return &timingWheelEventGenerator{
+		action:   action,
+		deadline: deadline,
+		interval: interval,
+		until:    until,
+		tick:     tick,
+
+		ctx: ctx,
+	}
+}
+
+func (t *timingWheelEventGenerator) Pop() *Event {
+	if t.Finished() {
+		panic(ErrEventGeneratorFinished)
+	}
+
+	event := t.nextEvent()
+	t.popped++
+
+	if t.interval == 0 {
+		t.done = true
+	} else {
+		t.deadline = t.deadline.Add(t.interval)
+	}
+
+	return event
+}
+
+func (t *timingWheelEventGenerator) Peek() Event {
+	if t.Finished() {
+		panic(ErrEventGeneratorFinished)
+	}
+
+	return *t.nextEvent()
+}
+
+func (t *timingWheelEventGenerator) nextEvent() *Event {
+	event := NewEvent(t.action, tick.RoundUp(t.deadline, t.tick), t.ctx)
+	event.Occurrence = t.popped + 1
+
+	return event
+}
+
+func (t *timingWheelEventGenerator) Finished() bool {
+	if t.done || t.ctx.Err() != nil {
+		return true
+	}
+
+	return t.until != nil && !t.deadline.Before(*t.until)
+}
+
+func (t *timingWheelEventGenerator) Clone() EventGenerator {
+	clone := *t
+	return &clone
+}
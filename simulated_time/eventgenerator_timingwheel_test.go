@@ -0,0 +1,113 @@
+package simulated_time
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/metamogul/timing"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_newTimingWheelEventGenerator(t *testing.T) {
+	t.Parallel()
+
+	type args struct {
+		action   timing.Action
+		deadline time.Time
+		interval time.Duration
+		until    *time.Time
+		tick     time.Duration
+	}
+
+	tests := []struct {
+		name         string
+		args         args
+		requirePanic bool
+	}{
+		{
+			name:         "no Action",
+			args:         args{tick: time.Millisecond},
+			requirePanic: true,
+		},
+		{
+			name:         "negative interval",
+			args:         args{action: timing.NewMockAction(t), interval: -time.Second, tick: time.Millisecond},
+			requirePanic: true,
+		},
+		{
+			name:         "tick is zero",
+			args:         args{action: timing.NewMockAction(t)},
+			requirePanic: true,
+		},
+		{
+			name: "success",
+			args: args{action: timing.NewMockAction(t), interval: time.Second, tick: time.Millisecond},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if tt.requirePanic {
+				require.Panics(t, func() {
+					_ = newTimingWheelEventGenerator(tt.args.action, tt.args.deadline, tt.args.interval, tt.args.until, tt.args.tick, context.Background())
+				})
+				return
+			}
+
+			got := newTimingWheelEventGenerator(tt.args.action, tt.args.deadline, tt.args.interval, tt.args.until, tt.args.tick, context.Background())
+			require.NotNil(t, got)
+			require.Equal(t, tt.args.tick, got.tick)
+			require.False(t, got.Finished())
+		})
+	}
+}
+
+func Test_timingWheelEventGenerator_single(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	generatorUnderTest := newTimingWheelEventGenerator(timing.NewMockAction(t), now.Add(3*time.Millisecond), 0, nil, 10*time.Millisecond, context.Background())
+
+	require.Equal(t, now.Add(10*time.Millisecond), generatorUnderTest.Peek().Time)
+	require.Equal(t, now.Add(10*time.Millisecond), generatorUnderTest.Pop().Time)
+	require.True(t, generatorUnderTest.Finished())
+
+	require.Panics(t, func() { _ = generatorUnderTest.Pop() })
+	require.Panics(t, func() { _ = generatorUnderTest.Peek() })
+}
+
+func Test_timingWheelEventGenerator_repeated(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	until := now.Add(40 * time.Millisecond)
+
+	generatorUnderTest := newTimingWheelEventGenerator(timing.NewMockAction(t), now.Add(12*time.Millisecond), 12*time.Millisecond, &until, 5*time.Millisecond, context.Background())
+
+	eventTimes := make([]time.Time, 0)
+	for !generatorUnderTest.Finished() {
+		eventTimes = append(eventTimes, generatorUnderTest.Pop().Time)
+	}
+
+	require.Equal(t, []time.Time{
+		now.Add(15 * time.Millisecond),
+		now.Add(25 * time.Millisecond),
+		now.Add(40 * time.Millisecond),
+	}, eventTimes)
+}
+
+func Test_timingWheelEventGenerator_cancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	generatorUnderTest := newTimingWheelEventGenerator(timing.NewMockAction(t), time.Time{}, time.Second, nil, time.Millisecond, ctx)
+	require.False(t, generatorUnderTest.Finished())
+
+	cancel()
+	require.True(t, generatorUnderTest.Finished())
+}
@@ -2,19 +2,44 @@ package simulated_time
 
 import (
 	"context"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/metamogul/timing"
+	"github.com/metamogul/timing/internal/instrument"
 )
 
+// AsyncEventScheduler performs every event in its own goroutine. Actions may
+// schedule new events and read the scheduler's time from any goroutine while
+// Forward or ForwardToNextEvent run.
 type AsyncEventScheduler struct {
 	*clock
 
+	// Metrics, if set, is reported to for every generator added afterwards.
+	// Durations are measured in simulated time.
+	Metrics timing.Metrics
+	// Hooks, if set, are called for every generator added afterwards and
+	// every event performed.
+	Hooks *timing.Hooks
+	// Middleware wraps every action performed, the first one outermost.
+	Middleware []timing.ActionMiddleware
+
+	// eventGeneratorsMu also guards advancing the clock, so that new events
+	// are never scheduled relative to a time the clock has already passed.
 	eventGenerators   *eventCombinator
-	eventGeneratorsMu sync.RWMutex
+	eventGeneratorsMu sync.Mutex
+
+	// eventIDs numbers the events performed.
+	eventIDs atomic.Uint64
 
 	wg sync.WaitGroup
+
+	// interleaver, if set, picks the order of events due at the same time,
+	// which are then performed one at a time, see
+	// NewSeededAsyncEventScheduler.
+	interleaver *interleaver
 }
 
 func NewAsyncEventScheduler(now time.Time) *AsyncEventScheduler {
@@ -34,100 +59,135 @@ func (a *AsyncEventScheduler) Forward(interval time.Duration) {
 }
 
 func (a *AsyncEventScheduler) performNextEvent(targetTime time.Time) (shouldContinue bool) {
-	a.eventGeneratorsMu.RLock()
-
-	if a.eventGenerators.Finished() {
-		a.clock.set(targetTime)
-		a.eventGeneratorsMu.RUnlock()
+	nextEvent := a.popNextEvent(&targetTime)
+	if nextEvent == nil {
 		return false
 	}
 
-	if a.eventGenerators.Peek().After(targetTime) {
-		a.clock.set(targetTime)
-		a.eventGeneratorsMu.RUnlock()
-		return false
-	}
-
-	nextEvent := a.eventGenerators.Pop()
-
-	a.eventGeneratorsMu.RUnlock()
+	a.performEvent(nextEvent)
 
-	a.clock.set(nextEvent.Time)
-
-	currentClock := a.clock.copy()
-	a.wg.Add(1)
-
-	switch schedulingAction := nextEvent.Action.(type) {
-	case SchedulingAction:
-		schedulingAction.eventLoopBlocker.Add(1)
-
-		go func() {
-			defer a.wg.Done()
-			nextEvent.Perform(newActionContext(nextEvent.Context, currentClock, schedulingAction.eventLoopBlocker))
-		}()
+	return true
+}
 
-		schedulingAction.eventLoopBlocker.Wait()
-	default:
-		go func() {
-			defer a.wg.Done()
-			nextEvent.Perform(newActionContext(nextEvent.Context, currentClock, nil))
-		}()
+func (a *AsyncEventScheduler) ForwardToNextEvent() {
+	nextEvent := a.popNextEvent(nil)
+	if nextEvent == nil {
+		return
 	}
 
-	return true
+	a.performEvent(nextEvent)
+
+	a.wg.Wait()
 }
 
-func (a *AsyncEventScheduler) ForwardToNextEvent() {
-	a.eventGeneratorsMu.RLock()
+// popNextEvent pops the next event and advances the clock to it. If there is
+// no event up to targetTime, it advances the clock to targetTime instead and
+// returns nil. A nil targetTime accepts any event.
+func (a *AsyncEventScheduler) popNextEvent(targetTime *time.Time) *Event {
+	a.eventGeneratorsMu.Lock()
+	defer a.eventGeneratorsMu.Unlock()
 
-	if a.eventGenerators.Finished() {
-		return
+	var choose func(n int) int
+	if a.interleaver != nil {
+		choose = a.interleaver.intN
 	}
 
-	nextEvent := a.eventGenerators.Pop()
+	nextEvent := a.eventGenerators.popUntil(targetTime, choose)
+	if nextEvent == nil {
+		if targetTime != nil {
+			a.clock.advance(timing.EventMetadata{ScheduledTime: *targetTime}, a.Hooks)
+		}
+		return nil
+	}
 
-	a.eventGeneratorsMu.RUnlock()
+	a.clock.advance(eventMetadata(nextEvent, 0), a.Hooks)
 
-	a.clock.set(nextEvent.Time)
+	return nextEvent
+}
 
+func (a *AsyncEventScheduler) performEvent(event *Event) {
 	currentClock := a.clock.copy()
+	metadata := eventMetadata(event, a.eventIDs.Add(1))
 	a.wg.Add(1)
 
-	switch schedulingAction := nextEvent.Action.(type) {
-	case SchedulingAction:
-		schedulingAction.eventLoopBlocker.Add(1)
+	// A seeded scheduler hands panics on to the goroutine forwarding it, like
+	// SerialEventScheduler does.
+	var panicked any
+	perform := func(actionContext *actionContext) {
+		defer a.wg.Done()
+		if a.interleaver != nil {
+			defer func() { panicked = recover() }()
+		}
 
-		go func() {
-			defer a.wg.Done()
-			nextEvent.Perform(newActionContext(nextEvent.Context, currentClock, schedulingAction.eventLoopBlocker))
-		}()
+		a.perform(event, actionContext)
+	}
 
+	switch schedulingAction := event.Action.(type) {
+	case SchedulingAction:
+		schedulingAction.eventLoopBlocker.Add(1)
+		go perform(newActionContext(event.Context, currentClock, schedulingAction.eventLoopBlocker, metadata, a))
 		schedulingAction.eventLoopBlocker.Wait()
 	default:
-		go func() {
-			defer a.wg.Done()
-			nextEvent.Perform(newActionContext(nextEvent.Context, currentClock, nil))
-		}()
+		go perform(newActionContext(event.Context, currentClock, nil, metadata, a))
 	}
 
-	a.wg.Wait()
+	if a.interleaver != nil {
+		a.wg.Wait()
+
+		if panicked != nil {
+			panic(panicked)
+		}
+	}
 }
 
 func (a *AsyncEventScheduler) PerformNow(action timing.Action, ctx context.Context) {
-	a.AddGenerator(newSingleEventGenerator(action, a.now, ctx))
+	a.addGeneratorAt(func(now time.Time) EventGenerator {
+		return newSingleEventGenerator(action, now, ctx)
+	})
 }
 
 func (a *AsyncEventScheduler) PerformAfter(action timing.Action, interval time.Duration, ctx context.Context) {
-	a.AddGenerator(newSingleEventGenerator(action, a.now.Add(interval), ctx))
+	a.addGeneratorAt(func(now time.Time) EventGenerator {
+		return newSingleEventGenerator(action, now.Add(interval), ctx)
+	})
 }
 
 func (a *AsyncEventScheduler) PerformRepeatedly(action timing.Action, until *time.Time, interval time.Duration, ctx context.Context) {
-	a.AddGenerator(newPeriodicEventGenerator(action, a.Now(), until, interval, ctx))
+	a.addGeneratorAt(func(now time.Time) EventGenerator {
+		return newPeriodicEventGenerator(action, now, until, interval, ctx)
+	})
 }
 
 func (a *AsyncEventScheduler) AddGenerator(generator EventGenerator) {
 	a.eventGeneratorsMu.Lock()
 	defer a.eventGeneratorsMu.Unlock()
 
-	a.eventGenerators.add(generator)
+	a.eventGenerators.add(instrumentEventGenerator(generator, a.Metrics, a.Hooks))
+}
+
+// addGeneratorAt creates a generator relative to the current time and adds
+// it before the clock can advance.
+func (a *AsyncEventScheduler) addGeneratorAt(newGenerator func(now time.Time) EventGenerator) {
+	a.eventGeneratorsMu.Lock()
+	defer a.eventGeneratorsMu.Unlock()
+
+	a.eventGenerators.add(instrumentEventGenerator(newGenerator(a.clock.Now()), a.Metrics, a.Hooks))
+}
+
+func (a *AsyncEventScheduler) perform(event *Event, actionContext *actionContext) {
+	action := timing.Chain(event.Action, slices.Concat(a.Middleware, timing.MiddlewareFrom(event.Context))...)
+
+	instrument.Perform(a.Metrics, a.Hooks, a.clock, actionContext.metadata, func() {
+		action.Perform(actionContext)
+	})
+}
+
+// WithDeadline returns a context that expires once the scheduler's simulated
+// time reaches deadline, see timing.WithDeadline.
+func (a *AsyncEventScheduler) WithDeadline(ctx context.Context, deadline time.Time) (context.Context, context.CancelFunc) {
+	return timing.WithDeadline(a, ctx, deadline)
+}
+
+func (a *AsyncEventScheduler) WithTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return timing.WithTimeout(a, ctx, timeout)
 }
@@ -4,10 +4,12 @@ import (
 	"context"
 	"slices"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/metamogul/timing"
+	"github.com/metamogul/timing/internal/recorder"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -114,6 +116,7 @@ func TestAsyncEventScheduler_Forward_RecursiveScheduling(t *testing.T) {
 
 	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
 
+	mu := sync.Mutex{}
 	eventTimes := make([]time.Time, 0)
 
 	s := &AsyncEventScheduler{
@@ -125,7 +128,9 @@ func TestAsyncEventScheduler_Forward_RecursiveScheduling(t *testing.T) {
 	innerAction.EXPECT().
 		Perform(mock.Anything).
 		Run(func(ctx timing.ActionContext) {
+			mu.Lock()
 			eventTimes = append(eventTimes, ctx.Clock().Now())
+			mu.Unlock()
 		}).
 		Once()
 
@@ -134,8 +139,12 @@ func TestAsyncEventScheduler_Forward_RecursiveScheduling(t *testing.T) {
 		Perform(mock.Anything).
 		Run(func(ctx timing.ActionContext) {
 			s.PerformAfter(innerAction, time.Second, context.Background())
-			ctx.DoneSchedulingNewEvents()
+
+			mu.Lock()
 			eventTimes = append(eventTimes, ctx.Clock().Now())
+			mu.Unlock()
+
+			ctx.DoneSchedulingNewEvents()
 		}).
 		Once()
 
@@ -374,3 +383,180 @@ func TestAsyncEventScheduler_AddGenerator(t *testing.T) {
 
 	require.Len(t, a.eventGenerators.activeGenerators, 1)
 }
+
+func TestAsyncEventScheduler_Metrics(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	metrics := recorder.NewMetrics()
+
+	eventSchedulerUnderTest := NewAsyncEventScheduler(now)
+	eventSchedulerUnderTest.Metrics = metrics
+
+	cancelledCtx, cancel := context.WithCancel(timing.WithJobName(context.Background(), "cancelled"))
+
+	mockAction := timing.NewMockAction(t)
+	mockAction.EXPECT().
+		Perform(mock.Anything).
+		Run(func(timing.ActionContext) { cancel() }).
+		Once()
+	eventSchedulerUnderTest.PerformAfter(mockAction, time.Second, timing.WithJobName(context.Background(), "performed"))
+	eventSchedulerUnderTest.PerformAfter(timing.NewMockAction(t), time.Minute, cancelledCtx)
+
+	eventSchedulerUnderTest.Forward(time.Second)
+	eventSchedulerUnderTest.Forward(time.Minute)
+
+	require.Equal(t, map[string]int{"performed": 1, "cancelled": 1}, metrics.Scheduled)
+	require.Equal(t, map[string]int{"cancelled": 1}, metrics.Cancelled)
+	require.Equal(t, map[string]int{"performed": 1}, metrics.Started)
+	require.Equal(t, map[string]int{"performed": 1}, metrics.Finished)
+	require.Equal(t, []time.Duration{0}, metrics.Lateness)
+}
+
+func TestAsyncEventScheduler_Hooks(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	hooks := &recordingHooks{}
+
+	eventSchedulerUnderTest := NewAsyncEventScheduler(now)
+	eventSchedulerUnderTest.Hooks = hooks.hooks()
+
+	cancelledCtx, cancel := context.WithCancel(timing.WithJobName(context.Background(), "cancelled"))
+
+	eventSchedulerUnderTest.PerformAfter(timing.ActionFunc(func(timing.ActionContext) {}), time.Minute, timing.WithJobName(context.Background(), "single"))
+	eventSchedulerUnderTest.PerformRepeatedly(timing.ActionFunc(func(timing.ActionContext) {}), ptr(now.Add(3*time.Minute)), time.Minute, timing.WithJobName(context.Background(), "repeated"))
+	eventSchedulerUnderTest.PerformAfter(timing.NewMockAction(t), 5*time.Minute, cancelledCtx)
+	cancel()
+
+	eventSchedulerUnderTest.Forward(3 * time.Minute)
+
+	require.ElementsMatch(t, []string{
+		"scheduled single 12:01",
+		"scheduled repeated 12:01",
+		"scheduled cancelled 12:05",
+		"cancelled cancelled 12:05",
+		"advanced single 12:01",
+		"finished single 12:01",
+		"before single 12:01",
+		"after single 12:01",
+		"before repeated 12:01",
+		"after repeated 12:01",
+		"advanced repeated 12:02",
+		"finished repeated 12:02",
+		"before repeated 12:02",
+		"after repeated 12:02",
+		"advanced  12:03",
+	}, hooks.recorded())
+}
+
+func TestAsyncEventScheduler_Middleware(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	performed := make([]timing.EventMetadata, 0)
+	mu := sync.Mutex{}
+
+	eventSchedulerUnderTest := NewAsyncEventScheduler(now)
+	eventSchedulerUnderTest.Middleware = []timing.ActionMiddleware{
+		func(action timing.Action) timing.Action {
+			return timing.ActionFunc(func(ctx timing.ActionContext) {
+				mu.Lock()
+				performed = append(performed, ctx.Metadata())
+				mu.Unlock()
+
+				action.Perform(ctx)
+			})
+		},
+	}
+
+	mockAction := timing.NewMockAction(t)
+	mockAction.EXPECT().
+		Perform(mock.Anything).
+		Once()
+
+	eventSchedulerUnderTest.PerformAfter(mockAction, time.Minute, timing.WithJobName(context.Background(), "job"))
+	eventSchedulerUnderTest.Forward(time.Minute)
+
+	require.Equal(t, []timing.EventMetadata{{ScheduledTime: now.Add(time.Minute), JobName: "job", EventID: 1, Occurrence: 1}}, performed)
+}
+
+func TestAsyncEventScheduler_WithTimeout(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	eventSchedulerUnderTest := NewAsyncEventScheduler(now)
+
+	ctx, cancel := eventSchedulerUnderTest.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	eventSchedulerUnderTest.Forward(59 * time.Second)
+	require.NoError(t, ctx.Err())
+
+	eventSchedulerUnderTest.Forward(time.Second)
+	require.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+
+	ctx, cancel = eventSchedulerUnderTest.WithDeadline(context.Background(), now)
+	defer cancel()
+	require.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+}
+
+// TestAsyncEventScheduler_concurrentScheduling schedules events from many
+// goroutines, inside and outside of actions, while the scheduler forwards.
+// It is meant to be run with the race detector.
+func TestAsyncEventScheduler_concurrentScheduling(t *testing.T) {
+	t.Parallel()
+
+	const (
+		schedulers = 20
+		events     = 50
+	)
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	eventSchedulerUnderTest := NewAsyncEventScheduler(now)
+
+	performed := atomic.Int64{}
+	countingAction := timing.ActionFunc(func(ctx timing.ActionContext) {
+		require.False(t, eventSchedulerUnderTest.Now().Before(ctx.Clock().Now()))
+		performed.Add(1)
+	})
+
+	spawningAction := timing.ActionFunc(func(timing.ActionContext) {
+		wg := sync.WaitGroup{}
+		for i := range events {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				eventSchedulerUnderTest.PerformAfter(countingAction, time.Duration(i)*time.Millisecond, context.Background())
+			}()
+		}
+		wg.Wait()
+	})
+
+	for i := range schedulers {
+		eventSchedulerUnderTest.PerformAfter(spawningAction, time.Duration(i)*time.Second, context.Background())
+	}
+
+	wg := sync.WaitGroup{}
+	for range schedulers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range events {
+				_ = eventSchedulerUnderTest.Now()
+				eventSchedulerUnderTest.PerformAfter(countingAction, time.Duration(i)*time.Second, context.Background())
+			}
+		}()
+	}
+
+	eventSchedulerUnderTest.Forward(time.Minute)
+	wg.Wait()
+	eventSchedulerUnderTest.Forward(time.Hour)
+
+	require.Equal(t, int64(2*schedulers*events), performed.Load())
+}
@@ -0,0 +1,277 @@
+package simulated_time
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/metamogul/timing"
+	"github.com/metamogul/timing/internal/instrument"
+)
+
+// IdleSkippingEventScheduler is meant for integration tests with real I/O.
+// While Forward runs, its time passes in real time as long as any action is
+// performing, and jumps straight to the next event once all of them have
+// returned. Every event is performed in its own goroutine. An action that
+// blocks counts as busy, unless it waits using Sleep.
+//
+// Outside of Forward and ForwardToNextEvent the time stands still, just like
+// with the other simulated schedulers. Scheduling is safe from any goroutine;
+// Forward and ForwardToNextEvent must not be called concurrently.
+type IdleSkippingEventScheduler struct {
+	// Metrics, if set, is reported to for every generator added afterwards.
+	// Durations are measured in simulated time.
+	Metrics timing.Metrics
+	// Hooks, if set, are called for every generator added afterwards and
+	// every event performed. OnClockAdvanced is called when the time jumps
+	// ahead, not while it passes in real time.
+	Hooks *timing.Hooks
+	// Middleware wraps every action performed, the first one outermost.
+	Middleware []timing.ActionMiddleware
+
+	mu sync.Mutex
+
+	// While forwarding, the time is base plus the real time passed since
+	// realBase, but never later than targetTime.
+	base       time.Time
+	realBase   time.Time
+	targetTime *time.Time
+
+	eventGenerators *eventCombinator
+	inFlight        int
+	changed         chan struct{}
+
+	// eventIDs numbers the events performed.
+	eventIDs atomic.Uint64
+}
+
+func NewIdleSkippingEventScheduler(now time.Time) *IdleSkippingEventScheduler {
+	return &IdleSkippingEventScheduler{
+		base:            now,
+		eventGenerators: newEventCombinator(),
+		changed:         make(chan struct{}, 1),
+	}
+}
+
+func (s *IdleSkippingEventScheduler) Now() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.now()
+}
+
+func (s *IdleSkippingEventScheduler) now() time.Time {
+	if s.targetTime == nil {
+		return s.base
+	}
+
+	now := s.base.Add(time.Since(s.realBase))
+	if now.After(*s.targetTime) {
+		return *s.targetTime
+	}
+
+	return now
+}
+
+func (s *IdleSkippingEventScheduler) set(now time.Time) {
+	s.base = now
+	s.realBase = time.Now()
+}
+
+// Forward advances the time by interval, performing all events up to then.
+// It returns once the time has been reached and all actions have returned.
+func (s *IdleSkippingEventScheduler) Forward(interval time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	targetTime := s.base.Add(interval)
+	s.targetTime = &targetTime
+	s.set(s.base)
+
+	for s.step(targetTime) {
+	}
+
+	if targetTime.After(s.now()) {
+		instrument.ClockAdvanced(s.Hooks, timing.EventMetadata{ScheduledTime: targetTime})
+	}
+
+	s.set(targetTime)
+	s.targetTime = nil
+}
+
+// ForwardToNextEvent forwards to the time of the next event.
+func (s *IdleSkippingEventScheduler) ForwardToNextEvent() {
+	s.mu.Lock()
+	nextEvent, ok := s.eventGenerators.next()
+	s.mu.Unlock()
+
+	if !ok {
+		return
+	}
+	interval := nextEvent.Time.Sub(s.base)
+
+	s.Forward(interval)
+}
+
+// step performs the next due event, skips ahead if all actions are idle, or
+// waits until either is possible. It must be called with s.mu held and
+// returns false once targetTime is reached and no action is performing.
+func (s *IdleSkippingEventScheduler) step(targetTime time.Time) (shouldContinue bool) {
+	now := s.now()
+
+	var next *Event
+	if nextEvent, ok := s.eventGenerators.next(); ok && !nextEvent.After(targetTime) {
+		next = &nextEvent
+	}
+
+	if s.inFlight == 0 {
+		if next == nil {
+			return false
+		}
+
+		if next.After(now) {
+			s.set(next.Time)
+			now = next.Time
+			instrument.ClockAdvanced(s.Hooks, eventMetadata(next, 0))
+		}
+	}
+
+	if next != nil && !next.After(now) {
+		// The event may have been cancelled since it was peeked.
+		if event := s.eventGenerators.popUntil(&now, nil); event != nil {
+			s.performEvent(event)
+		}
+		return true
+	}
+
+	wakeAt := targetTime
+	if next != nil {
+		wakeAt = next.Time
+	}
+
+	s.mu.Unlock()
+	defer s.mu.Lock()
+
+	if !wakeAt.After(now) {
+		<-s.changed
+		return true
+	}
+
+	timer := time.NewTimer(wakeAt.Sub(now))
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-s.changed:
+	}
+
+	return true
+}
+
+func (s *IdleSkippingEventScheduler) performEvent(event *Event) {
+	// A woken action is busy again right away, not only once the wake
+	// action has returned.
+	if _, ok := event.Action.(wakeAction); ok {
+		s.inFlight++
+	}
+	s.inFlight++
+
+	go func() {
+		defer s.actionReturned()
+		s.perform(event)
+	}()
+}
+
+func (s *IdleSkippingEventScheduler) actionReturned() {
+	s.mu.Lock()
+	s.inFlight--
+	s.mu.Unlock()
+
+	s.notify()
+}
+
+func (s *IdleSkippingEventScheduler) notify() {
+	select {
+	case s.changed <- struct{}{}:
+	default:
+	}
+}
+
+// Sleep pauses the calling action for interval of the scheduler's time. The
+// action counts as idle meanwhile, so the scheduler may skip ahead to wake
+// it. Sleep must only be called from actions performed by s.
+func (s *IdleSkippingEventScheduler) Sleep(interval time.Duration) {
+	woken := make(chan struct{})
+
+	s.mu.Lock()
+	s.inFlight--
+	s.eventGenerators.add(newSingleEventGenerator(wakeAction(woken), s.now().Add(interval), context.Background()))
+	s.mu.Unlock()
+
+	s.notify()
+	<-woken
+}
+
+// wakeAction resumes an action blocked in Sleep.
+type wakeAction chan struct{}
+
+func (w wakeAction) Perform(timing.ActionContext) {
+	close(w)
+}
+
+func (s *IdleSkippingEventScheduler) perform(event *Event) {
+	if wake, ok := event.Action.(wakeAction); ok {
+		wake.Perform(nil)
+		return
+	}
+
+	metadata := eventMetadata(event, s.eventIDs.Add(1))
+	actionContext := newActionContext(event.Context, s, nil, metadata, s)
+	action := timing.Chain(event.Action, slices.Concat(s.Middleware, timing.MiddlewareFrom(event.Context))...)
+
+	instrument.Perform(s.Metrics, s.Hooks, s, metadata, func() {
+		action.Perform(actionContext)
+	})
+}
+
+func (s *IdleSkippingEventScheduler) PerformNow(action timing.Action, ctx context.Context) {
+	s.addGeneratorAt(func(now time.Time) EventGenerator {
+		return newSingleEventGenerator(action, now, ctx)
+	})
+}
+
+func (s *IdleSkippingEventScheduler) PerformAfter(action timing.Action, interval time.Duration, ctx context.Context) {
+	s.addGeneratorAt(func(now time.Time) EventGenerator {
+		return newSingleEventGenerator(action, now.Add(interval), ctx)
+	})
+}
+
+func (s *IdleSkippingEventScheduler) PerformRepeatedly(action timing.Action, until *time.Time, interval time.Duration, ctx context.Context) {
+	s.addGeneratorAt(func(now time.Time) EventGenerator {
+		return newPeriodicEventGenerator(action, now, until, interval, ctx)
+	})
+}
+
+func (s *IdleSkippingEventScheduler) AddGenerator(generator EventGenerator) {
+	s.addGeneratorAt(func(time.Time) EventGenerator { return generator })
+}
+
+func (s *IdleSkippingEventScheduler) addGeneratorAt(newGenerator func(now time.Time) EventGenerator) {
+	s.mu.Lock()
+	s.eventGenerators.add(instrumentEventGenerator(newGenerator(s.now()), s.Metrics, s.Hooks))
+	s.mu.Unlock()
+
+	s.notify()
+}
+
+// WithDeadline returns a context that expires once the scheduler's time
+// reaches deadline, see timing.WithDeadline.
+func (s *IdleSkippingEventScheduler) WithDeadline(ctx context.Context, deadline time.Time) (context.Context, context.CancelFunc) {
+	return timing.WithDeadline(s, ctx, deadline)
+}
+
+func (s *IdleSkippingEventScheduler) WithTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return timing.WithTimeout(s, ctx, timeout)
+}
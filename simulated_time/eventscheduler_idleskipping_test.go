@@ -0,0 +1,240 @@
+package simulated_time
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/metamogul/timing"
+	"github.com/metamogul/timing/internal/recorder"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewIdleSkippingEventScheduler(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	newEventScheduler := NewIdleSkippingEventScheduler(now)
+
+	require.NotNil(t, newEventScheduler)
+	require.Equal(t, now, newEventScheduler.Now())
+	require.NotNil(t, newEventScheduler.eventGenerators)
+
+	time.Sleep(time.Millisecond)
+	require.Equal(t, now, newEventScheduler.Now())
+}
+
+func TestIdleSkippingEventScheduler_Forward_skipsIdleTime(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	eventSchedulerUnderTest := NewIdleSkippingEventScheduler(now)
+
+	mu := sync.Mutex{}
+	performedAt := make([]time.Time, 0)
+	recordingAction := timing.ActionFunc(func(ctx timing.ActionContext) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		performedAt = append(performedAt, ctx.Metadata().ScheduledTime)
+	})
+
+	eventSchedulerUnderTest.PerformAfter(recordingAction, time.Hour, context.Background())
+	eventSchedulerUnderTest.PerformRepeatedly(recordingAction, nil, 24*time.Hour, context.Background())
+
+	start := time.Now()
+	eventSchedulerUnderTest.Forward(48 * time.Hour)
+
+	require.Less(t, time.Since(start), time.Second)
+	require.Equal(t, now.Add(48*time.Hour), eventSchedulerUnderTest.Now())
+	require.Equal(t, []time.Time{now.Add(time.Hour), now.Add(24 * time.Hour), now.Add(48 * time.Hour)}, performedAt)
+}
+
+func TestIdleSkippingEventScheduler_Forward_realTimeWhileBusy(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	eventSchedulerUnderTest := NewIdleSkippingEventScheduler(now)
+
+	mu := sync.Mutex{}
+	performed := make([]string, 0)
+	record := func(name string) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		performed = append(performed, name)
+	}
+
+	eventSchedulerUnderTest.PerformAfter(timing.ActionFunc(func(timing.ActionContext) {
+		time.Sleep(100 * time.Millisecond)
+		record("busy")
+	}), time.Minute, context.Background())
+	eventSchedulerUnderTest.PerformAfter(timing.ActionFunc(func(ctx timing.ActionContext) {
+		require.False(t, ctx.Clock().Now().Before(now.Add(time.Minute+10*time.Millisecond)))
+		record("during")
+	}), time.Minute+10*time.Millisecond, context.Background())
+	eventSchedulerUnderTest.PerformAfter(timing.ActionFunc(func(timing.ActionContext) {
+		record("after")
+	}), time.Minute+time.Second, context.Background())
+
+	eventSchedulerUnderTest.Forward(time.Hour)
+
+	require.Equal(t, []string{"during", "busy", "after"}, performed)
+	require.Equal(t, now.Add(time.Hour), eventSchedulerUnderTest.Now())
+}
+
+func TestIdleSkippingEventScheduler_Forward_schedulingFromActions(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	eventSchedulerUnderTest := NewIdleSkippingEventScheduler(now)
+
+	performedAt := make(chan time.Time, 1)
+	eventSchedulerUnderTest.PerformNow(timing.ActionFunc(func(timing.ActionContext) {
+		go eventSchedulerUnderTest.PerformAfter(timing.ActionFunc(func(ctx timing.ActionContext) {
+			performedAt <- ctx.Metadata().ScheduledTime
+		}), time.Hour, context.Background())
+		time.Sleep(10 * time.Millisecond)
+	}), context.Background())
+
+	eventSchedulerUnderTest.Forward(2 * time.Hour)
+
+	select {
+	case scheduledTime := <-performedAt:
+		require.False(t, scheduledTime.Before(now.Add(time.Hour)))
+		require.True(t, scheduledTime.Before(now.Add(time.Hour+time.Second)))
+	default:
+		require.Fail(t, "action wasn't performed")
+	}
+}
+
+// TestIdleSkippingEventScheduler_concurrentCancelling cancels repeated events
+// from helper goroutines while the test forwards the scheduler. It is meant to
+// be run with the race detector.
+func TestIdleSkippingEventScheduler_concurrentCancelling(t *testing.T) {
+	t.Parallel()
+
+	const generators = 200
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	eventSchedulerUnderTest := NewIdleSkippingEventScheduler(now)
+
+	performed := atomic.Int32{}
+	countingAction := timing.ActionFunc(func(timing.ActionContext) {
+		performed.Add(1)
+	})
+
+	wg := sync.WaitGroup{}
+	for i := range generators {
+		ctx, cancel := context.WithCancel(context.Background())
+		eventSchedulerUnderTest.PerformRepeatedly(countingAction, nil, time.Minute, ctx)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			time.Sleep(time.Duration(i) * 100 * time.Microsecond)
+			cancel()
+		}()
+	}
+
+	eventSchedulerUnderTest.Forward(24 * time.Hour)
+	wg.Wait()
+
+	require.Positive(t, performed.Load())
+	require.Equal(t, now.Add(24*time.Hour), eventSchedulerUnderTest.Now())
+}
+
+func TestIdleSkippingEventScheduler_ForwardToNextEvent(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	eventSchedulerUnderTest := NewIdleSkippingEventScheduler(now)
+	eventSchedulerUnderTest.ForwardToNextEvent()
+	require.Equal(t, now, eventSchedulerUnderTest.Now())
+
+	performed := 0
+	countingAction := timing.ActionFunc(func(timing.ActionContext) { performed++ })
+
+	eventSchedulerUnderTest.PerformAfter(countingAction, time.Hour, context.Background())
+	eventSchedulerUnderTest.PerformAfter(countingAction, 2*time.Hour, context.Background())
+
+	eventSchedulerUnderTest.ForwardToNextEvent()
+	require.Equal(t, now.Add(time.Hour), eventSchedulerUnderTest.Now())
+	require.Equal(t, 1, performed)
+}
+
+func TestIdleSkippingEventScheduler_Sleep(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	eventSchedulerUnderTest := NewIdleSkippingEventScheduler(now)
+
+	wokenAt := make(chan time.Time, 1)
+	eventSchedulerUnderTest.PerformNow(timing.ActionFunc(func(ctx timing.ActionContext) {
+		eventSchedulerUnderTest.Sleep(time.Hour)
+		wokenAt <- ctx.Clock().Now()
+	}), context.Background())
+
+	start := time.Now()
+	eventSchedulerUnderTest.Forward(2 * time.Hour)
+
+	require.Less(t, time.Since(start), time.Second)
+	require.Equal(t, now.Add(2*time.Hour), eventSchedulerUnderTest.Now())
+
+	select {
+	case woken := <-wokenAt:
+		require.False(t, woken.Before(now.Add(time.Hour)))
+	default:
+		require.Fail(t, "action wasn't woken")
+	}
+}
+
+func TestIdleSkippingEventScheduler_Metrics(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	metrics := recorder.NewMetrics()
+
+	eventSchedulerUnderTest := NewIdleSkippingEventScheduler(now)
+	eventSchedulerUnderTest.Metrics = metrics
+
+	eventSchedulerUnderTest.PerformAfter(timing.ActionFunc(func(timing.ActionContext) {}), time.Minute, timing.WithJobName(context.Background(), "job"))
+	eventSchedulerUnderTest.Forward(time.Minute)
+
+	require.Equal(t, map[string]int{"job": 1}, metrics.Scheduled)
+	require.Equal(t, map[string]int{"job": 1}, metrics.Started)
+	require.Equal(t, map[string]int{"job": 1}, metrics.Finished)
+}
+
+func TestIdleSkippingEventScheduler_Hooks(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	hooks := &recordingHooks{}
+
+	eventSchedulerUnderTest := NewIdleSkippingEventScheduler(now)
+	eventSchedulerUnderTest.Hooks = hooks.hooks()
+
+	eventSchedulerUnderTest.PerformAfter(timing.ActionFunc(func(timing.ActionContext) {}), time.Minute, timing.WithJobName(context.Background(), "job"))
+	eventSchedulerUnderTest.Forward(2 * time.Minute)
+
+	require.Equal(t, []string{
+		"scheduled job 12:01",
+		"advanced job 12:01",
+		"finished job 12:01",
+		"before job 12:01",
+		"after job 12:01",
+		"advanced  12:02",
+	}, hooks.recorded())
+}
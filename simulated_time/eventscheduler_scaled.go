@@ -0,0 +1,247 @@
+package simulated_time
+
+import (
+	"context"
+	"math"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/metamogul/timing"
+	"github.com/metamogul/timing/internal/instrument"
+)
+
+// ScaledEventScheduler runs simulated time alongside real time, multiplied by
+// a factor that can be changed at any time, e.g. to run a demo at 60x or a
+// soak test at 3600x. Actions are performed in their own goroutine once
+// simulated time reaches them, much like system.EventScheduler does in real
+// time. All methods are safe for concurrent use.
+type ScaledEventScheduler struct {
+	// Metrics, if set, is reported to for every generator added afterwards.
+	// Durations are measured in simulated time.
+	Metrics timing.Metrics
+	// Hooks, if set, are called for every generator added afterwards and
+	// every event performed. OnClockAdvanced is never called, as the time
+	// passes continuously.
+	Hooks *timing.Hooks
+	// Middleware wraps every action performed, the first one outermost.
+	Middleware []timing.ActionMiddleware
+
+	mu sync.Mutex
+
+	// Simulated time is base plus the real time passed since realBase,
+	// multiplied by factor.
+	base        time.Time
+	realBase    time.Time
+	factor      float64
+	pausedAfter float64
+
+	eventGenerators *eventCombinator
+	running         bool
+	wakeup          chan struct{}
+
+	// eventIDs numbers the events performed.
+	eventIDs atomic.Uint64
+}
+
+func NewScaledEventScheduler(now time.Time, factor float64) *ScaledEventScheduler {
+	if factor < 0 {
+		panic("factor can't be negative")
+	}
+
+	return &ScaledEventScheduler{
+		base:            now,
+		realBase:        time.Now(),
+		factor:          factor,
+		eventGenerators: newEventCombinator(),
+		wakeup:          make(chan struct{}, 1),
+	}
+}
+
+func (s *ScaledEventScheduler) Now() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.now(time.Now())
+}
+
+func (s *ScaledEventScheduler) now(realNow time.Time) time.Time {
+	return s.base.Add(time.Duration(float64(realNow.Sub(s.realBase)) * s.factor))
+}
+
+func (s *ScaledEventScheduler) Factor() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.factor
+}
+
+// SetFactor changes how fast simulated time runs from now on. A factor of
+// zero stops it.
+func (s *ScaledEventScheduler) SetFactor(factor float64) {
+	if factor < 0 {
+		panic("factor can't be negative")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.setFactor(factor)
+}
+
+func (s *ScaledEventScheduler) setFactor(factor float64) {
+	realNow := time.Now()
+
+	s.base = s.now(realNow)
+	s.realBase = realNow
+	s.factor = factor
+
+	s.wake()
+}
+
+// Pause stops simulated time until Resume is called.
+func (s *ScaledEventScheduler) Pause() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.factor == 0 {
+		return
+	}
+
+	s.pausedAfter = s.factor
+	s.setFactor(0)
+}
+
+// Resume continues simulated time at the factor it ran at before Pause.
+func (s *ScaledEventScheduler) Resume() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.factor != 0 || s.pausedAfter == 0 {
+		return
+	}
+
+	s.setFactor(s.pausedAfter)
+	s.pausedAfter = 0
+}
+
+func (s *ScaledEventScheduler) PerformNow(action timing.Action, ctx context.Context) {
+	s.addGeneratorAt(func(now time.Time) EventGenerator {
+		return newSingleEventGenerator(action, now, ctx)
+	})
+}
+
+func (s *ScaledEventScheduler) PerformAfter(action timing.Action, interval time.Duration, ctx context.Context) {
+	s.addGeneratorAt(func(now time.Time) EventGenerator {
+		return newSingleEventGenerator(action, now.Add(interval), ctx)
+	})
+}
+
+func (s *ScaledEventScheduler) PerformRepeatedly(action timing.Action, until *time.Time, interval time.Duration, ctx context.Context) {
+	s.addGeneratorAt(func(now time.Time) EventGenerator {
+		return newPeriodicEventGenerator(action, now, until, interval, ctx)
+	})
+}
+
+func (s *ScaledEventScheduler) AddGenerator(generator EventGenerator) {
+	s.addGeneratorAt(func(time.Time) EventGenerator { return generator })
+}
+
+func (s *ScaledEventScheduler) addGeneratorAt(newGenerator func(now time.Time) EventGenerator) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.eventGenerators.add(instrumentEventGenerator(newGenerator(s.now(time.Now())), s.Metrics, s.Hooks))
+
+	if !s.running {
+		s.running = true
+		go s.run()
+		return
+	}
+
+	s.wake()
+}
+
+func (s *ScaledEventScheduler) wake() {
+	select {
+	case s.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+// run performs due events until there are none left. It sleeps for the real
+// time until the next event is due, or until woken up by a new event or a
+// changed factor.
+func (s *ScaledEventScheduler) run() {
+	timer := time.NewTimer(time.Hour)
+	stopTimer(timer)
+
+	for {
+		s.mu.Lock()
+
+		next, ok := s.eventGenerators.next()
+		if !ok {
+			s.running = false
+			s.mu.Unlock()
+			return
+		}
+
+		now := s.now(time.Now())
+		if remaining := next.Time.Sub(now); remaining > 0 {
+			factor := s.factor
+			s.mu.Unlock()
+
+			if factor == 0 {
+				<-s.wakeup
+				continue
+			}
+
+			timer.Reset(time.Duration(math.Ceil(float64(remaining) / factor)))
+			select {
+			case <-timer.C:
+			case <-s.wakeup:
+				stopTimer(timer)
+			}
+
+			continue
+		}
+
+		// The event may have been cancelled since it was peeked.
+		event := s.eventGenerators.popUntil(&now, nil)
+		s.mu.Unlock()
+
+		if event != nil {
+			go s.perform(event)
+		}
+	}
+}
+
+func (s *ScaledEventScheduler) perform(event *Event) {
+	metadata := eventMetadata(event, s.eventIDs.Add(1))
+	actionContext := newActionContext(event.Context, s, nil, metadata, s)
+	action := timing.Chain(event.Action, slices.Concat(s.Middleware, timing.MiddlewareFrom(event.Context))...)
+
+	instrument.Perform(s.Metrics, s.Hooks, s, metadata, func() {
+		action.Perform(actionContext)
+	})
+}
+
+// WithDeadline returns a context that expires once the scheduler's simulated
+// time reaches deadline, see timing.WithDeadline.
+func (s *ScaledEventScheduler) WithDeadline(ctx context.Context, deadline time.Time) (context.Context, context.CancelFunc) {
+	return timing.WithDeadline(s, ctx, deadline)
+}
+
+func (s *ScaledEventScheduler) WithTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return timing.WithTimeout(s, ctx, timeout)
+}
+
+func stopTimer(timer *time.Timer) {
+	if !timer.Stop() {
+		select {
+		case <-timer.C:
+		default:
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package simulated_time
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/metamogul/timing"
+	"github.com/metamogul/timing/internal/recorder"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewScaledEventScheduler(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	newEventScheduler := NewScaledEventScheduler(now, 0)
+
+	require.NotNil(t, newEventScheduler)
+	require.Equal(t, now, newEventScheduler.Now())
+	require.Equal(t, 0.0, newEventScheduler.Factor())
+	require.NotNil(t, newEventScheduler.eventGenerators)
+
+	require.PanicsWithValue(t, "factor can't be negative", func() { NewScaledEventScheduler(now, -1) })
+}
+
+func TestScaledEventScheduler_Now(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	eventSchedulerUnderTest := NewScaledEventScheduler(now, 1000)
+
+	start := time.Now()
+	time.Sleep(10 * time.Millisecond)
+	realElapsed := time.Since(start)
+
+	simulatedElapsed := eventSchedulerUnderTest.Now().Sub(now)
+	require.GreaterOrEqual(t, simulatedElapsed, 1000*realElapsed-time.Second)
+	require.Less(t, simulatedElapsed, 1000*realElapsed+10*time.Second)
+}
+
+func TestScaledEventScheduler_PerformAfter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	eventSchedulerUnderTest := NewScaledEventScheduler(now, float64(time.Hour/(10*time.Millisecond)))
+
+	performed := make(chan [2]time.Time, 1)
+	eventSchedulerUnderTest.PerformAfter(timing.ActionFunc(func(ctx timing.ActionContext) {
+		performed <- [2]time.Time{ctx.Metadata().ScheduledTime, ctx.Clock().Now()}
+	}), time.Hour, context.Background())
+
+	select {
+	case times := <-performed:
+		require.True(t, times[0].Equal(now.Add(time.Hour)) || times[0].After(now.Add(time.Hour)))
+		require.False(t, times[1].Before(times[0]))
+	case <-time.After(time.Second):
+		require.Fail(t, "action wasn't performed")
+	}
+}
+
+func TestScaledEventScheduler_PerformRepeatedly(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	eventSchedulerUnderTest := NewScaledEventScheduler(now, 0)
+
+	performed := atomic.Int32{}
+	eventSchedulerUnderTest.PerformRepeatedly(timing.ActionFunc(func(timing.ActionContext) {
+		performed.Add(1)
+	}), ptr(now.Add(5*time.Minute)), time.Minute, context.Background())
+
+	eventSchedulerUnderTest.SetFactor(float64(time.Minute / time.Millisecond))
+
+	require.Eventually(t, func() bool { return performed.Load() == 4 }, time.Second, time.Millisecond)
+
+	time.Sleep(5 * time.Millisecond)
+	require.Equal(t, int32(4), performed.Load())
+}
+
+func TestScaledEventScheduler_PauseResume(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	eventSchedulerUnderTest := NewScaledEventScheduler(now, float64(time.Minute/time.Millisecond))
+	eventSchedulerUnderTest.Pause()
+	require.Equal(t, 0.0, eventSchedulerUnderTest.Factor())
+
+	performed := atomic.Bool{}
+	eventSchedulerUnderTest.PerformAfter(timing.ActionFunc(func(timing.ActionContext) {
+		performed.Store(true)
+	}), time.Minute, context.Background())
+
+	pausedAt := eventSchedulerUnderTest.Now()
+	time.Sleep(10 * time.Millisecond)
+	require.Equal(t, pausedAt, eventSchedulerUnderTest.Now())
+	require.False(t, performed.Load())
+
+	eventSchedulerUnderTest.Resume()
+	require.Equal(t, float64(time.Minute/time.Millisecond), eventSchedulerUnderTest.Factor())
+	require.Eventually(t, performed.Load, time.Second, time.Millisecond)
+}
+
+func TestScaledEventScheduler_SetFactor(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	eventSchedulerUnderTest := NewScaledEventScheduler(now, 1)
+
+	performed := atomic.Bool{}
+	eventSchedulerUnderTest.PerformAfter(timing.ActionFunc(func(timing.ActionContext) {
+		performed.Store(true)
+	}), time.Hour, context.Background())
+
+	time.Sleep(5 * time.Millisecond)
+	require.False(t, performed.Load())
+
+	eventSchedulerUnderTest.SetFactor(float64(time.Hour / time.Millisecond))
+	require.Eventually(t, performed.Load, time.Second, time.Millisecond)
+
+	require.PanicsWithValue(t, "factor can't be negative", func() { eventSchedulerUnderTest.SetFactor(-1) })
+}
+
+func TestScaledEventScheduler_Metrics(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	metrics := recorder.NewMetrics()
+
+	eventSchedulerUnderTest := NewScaledEventScheduler(now, float64(time.Minute/time.Millisecond))
+	eventSchedulerUnderTest.Metrics = metrics
+
+	eventSchedulerUnderTest.PerformAfter(timing.ActionFunc(func(timing.ActionContext) {}), time.Minute, timing.WithJobName(context.Background(), "job"))
+
+	require.Eventually(t, func() bool { return metrics.Count(metrics.Finished, "job") == 1 }, time.Second, time.Millisecond)
+	require.Equal(t, 1, metrics.Count(metrics.Scheduled, "job"))
+	require.Equal(t, 1, metrics.Count(metrics.Started, "job"))
+}
+
+// TestScaledEventScheduler_concurrentCancelling cancels repeated events from
+// helper goroutines while the scheduler performs them. It is meant to be run
+// with the race detector.
+func TestScaledEventScheduler_concurrentCancelling(t *testing.T) {
+	t.Parallel()
+
+	const generators = 100
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	eventSchedulerUnderTest := NewScaledEventScheduler(now, float64(time.Minute/time.Millisecond))
+
+	performed := atomic.Int32{}
+	countingAction := timing.ActionFunc(func(timing.ActionContext) {
+		performed.Add(1)
+	})
+
+	wg := sync.WaitGroup{}
+	for i := range generators {
+		ctx, cancel := context.WithCancel(context.Background())
+		eventSchedulerUnderTest.PerformRepeatedly(countingAction, nil, time.Second, ctx)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			time.Sleep(time.Duration(i) * 50 * time.Microsecond)
+			cancel()
+		}()
+	}
+	wg.Wait()
+
+	require.Eventually(t, func() bool {
+		eventSchedulerUnderTest.mu.Lock()
+		defer eventSchedulerUnderTest.mu.Unlock()
+
+		return !eventSchedulerUnderTest.running
+	}, time.Second, time.Millisecond)
+	require.Positive(t, performed.Load())
+}
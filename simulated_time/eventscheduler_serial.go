@@ -3,13 +3,40 @@ package simulated_time
 import (
 	"context"
 	"github.com/metamogul/timing"
+	"github.com/metamogul/timing/internal/instrument"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// SerialEventScheduler performs events one at a time, on the goroutine that
+// calls Forward or ForwardToNextEvent, in the order of their times.
+//
+// Scheduling, i.e. PerformNow, PerformAfter, PerformRepeatedly, AddGenerator
+// and Now, is safe from any goroutine, including while the scheduler
+// forwards. Events scheduled for a time that the current Forward reaches are
+// performed by it. Forward and ForwardToNextEvent themselves must not be
+// called concurrently.
 type SerialEventScheduler struct {
 	*clock
 
-	eventGenerators *eventCombinator
+	// Metrics, if set, is reported to for every generator added afterwards.
+	// Durations are measured in simulated time.
+	Metrics timing.Metrics
+	// Hooks, if set, are called for every generator added afterwards and
+	// every event performed.
+	Hooks *timing.Hooks
+	// Middleware wraps every action performed, the first one outermost.
+	Middleware []timing.ActionMiddleware
+
+	// eventGeneratorsMu also guards advancing the clock, so that new events
+	// are never scheduled relative to a time the clock has already passed.
+	eventGenerators   *eventCombinator
+	eventGeneratorsMu sync.Mutex
+
+	// eventIDs numbers the events performed.
+	eventIDs atomic.Uint64
 }
 
 func NewSerialEventScheduler(now time.Time) *SerialEventScheduler {
@@ -27,47 +54,95 @@ func (s *SerialEventScheduler) Forward(interval time.Duration) {
 }
 
 func (s *SerialEventScheduler) performNextEvent(targetTime time.Time) (shouldContinue bool) {
-	if s.eventGenerators.Finished() {
-		s.clock.set(targetTime)
+	nextEvent := s.popNextEvent(&targetTime)
+	if nextEvent == nil {
 		return false
 	}
 
-	if s.eventGenerators.Peek().After(targetTime) {
-		s.clock.set(targetTime)
-		return false
-	}
-
-	nextEvent := s.eventGenerators.Pop()
-	s.clock.set(nextEvent.Time)
-
-	nextEvent.Perform(newActionContext(nextEvent.Context, s.clock.copy(), nil))
+	s.perform(nextEvent)
 
 	return true
 }
 
 func (s *SerialEventScheduler) ForwardToNextEvent() {
-	if s.eventGenerators.Finished() {
+	nextEvent := s.popNextEvent(nil)
+	if nextEvent == nil {
 		return
 	}
 
-	nextEvent := s.eventGenerators.Pop()
-	s.clock.set(nextEvent.Time)
+	s.perform(nextEvent)
+}
+
+// popNextEvent pops the next event and advances the clock to it. If there is
+// no event up to targetTime, it advances the clock to targetTime instead and
+// returns nil. A nil targetTime accepts any event.
+func (s *SerialEventScheduler) popNextEvent(targetTime *time.Time) *Event {
+	s.eventGeneratorsMu.Lock()
+	defer s.eventGeneratorsMu.Unlock()
+
+	nextEvent := s.eventGenerators.popUntil(targetTime, nil)
+	if nextEvent == nil {
+		if targetTime != nil {
+			s.clock.advance(timing.EventMetadata{ScheduledTime: *targetTime}, s.Hooks)
+		}
+		return nil
+	}
+
+	s.clock.advance(eventMetadata(nextEvent, 0), s.Hooks)
 
-	nextEvent.Perform(newActionContext(nextEvent.Context, s.clock.copy(), nil))
+	return nextEvent
 }
 
 func (s *SerialEventScheduler) PerformNow(action timing.Action, ctx context.Context) {
-	s.AddGenerator(newSingleEventGenerator(action, s.now, ctx))
+	s.addGeneratorAt(func(now time.Time) EventGenerator {
+		return newSingleEventGenerator(action, now, ctx)
+	})
 }
 
 func (s *SerialEventScheduler) PerformAfter(action timing.Action, interval time.Duration, ctx context.Context) {
-	s.AddGenerator(newSingleEventGenerator(action, s.now.Add(interval), ctx))
+	s.addGeneratorAt(func(now time.Time) EventGenerator {
+		return newSingleEventGenerator(action, now.Add(interval), ctx)
+	})
 }
 
 func (s *SerialEventScheduler) PerformRepeatedly(action timing.Action, until *time.Time, interval time.Duration, ctx context.Context) {
-	s.AddGenerator(newPeriodicEventGenerator(action, s.Now(), until, interval, ctx))
+	s.addGeneratorAt(func(now time.Time) EventGenerator {
+		return newPeriodicEventGenerator(action, now, until, interval, ctx)
+	})
 }
 
 func (s *SerialEventScheduler) AddGenerator(generator EventGenerator) {
-	s.eventGenerators.add(generator)
+	s.eventGeneratorsMu.Lock()
+	defer s.eventGeneratorsMu.Unlock()
+
+	s.eventGenerators.add(instrumentEventGenerator(generator, s.Metrics, s.Hooks))
+}
+
+// addGeneratorAt creates a generator relative to the current time and adds
+// it before the clock can advance.
+func (s *SerialEventScheduler) addGeneratorAt(newGenerator func(now time.Time) EventGenerator) {
+	s.eventGeneratorsMu.Lock()
+	defer s.eventGeneratorsMu.Unlock()
+
+	s.eventGenerators.add(instrumentEventGenerator(newGenerator(s.clock.Now()), s.Metrics, s.Hooks))
+}
+
+func (s *SerialEventScheduler) perform(event *Event) {
+	metadata := eventMetadata(event, s.eventIDs.Add(1))
+	actionContext := newActionContext(event.Context, s.clock.copy(), nil, metadata, s)
+	action := timing.Chain(event.Action, slices.Concat(s.Middleware, timing.MiddlewareFrom(event.Context))...)
+
+	instrument.Perform(s.Metrics, s.Hooks, s.clock, metadata, func() {
+		action.Perform(actionContext)
+	})
+}
+
+// WithDeadline returns a context that expires once the scheduler's simulated
+// time reaches deadline, see timing.WithDeadline.
+func (s *SerialEventScheduler) WithDeadline(ctx context.Context, deadline time.Time) (context.Context, context.CancelFunc) {
+	return timing.WithDeadline(s, ctx, deadline)
+}
+
+func (s *SerialEventScheduler) WithTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return timing.WithTimeout(s, ctx, timeout)
 }
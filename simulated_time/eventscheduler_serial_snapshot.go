@@ -0,0 +1,65 @@
+package simulated_time
+
+import (
+	"slices"
+	"time"
+
+	"github.com/metamogul/timing"
+)
+
+// SerialEventSchedulerSnapshot is the state of a SerialEventScheduler at one
+// point in time: its clock and its pending generators, which can be forked
+// into independent schedulers any number of times.
+type SerialEventSchedulerSnapshot struct {
+	now             time.Time
+	middleware      []timing.ActionMiddleware
+	eventGenerators *eventCombinator
+	eventIDs        uint64
+}
+
+// Snapshot captures the scheduler's state, e.g. from within an action to
+// compare what happens if it fails with what happens if it succeeds. It
+// panics with ErrEventGeneratorNotCloneable unless all pending generators
+// are cloneable.
+func (s *SerialEventScheduler) Snapshot() *SerialEventSchedulerSnapshot {
+	s.eventGeneratorsMu.Lock()
+	defer s.eventGeneratorsMu.Unlock()
+
+	return &SerialEventSchedulerSnapshot{
+		now:             s.clock.Now(),
+		middleware:      slices.Clone(s.Middleware),
+		eventGenerators: s.eventGenerators.clone(),
+		eventIDs:        s.eventIDs.Load(),
+	}
+}
+
+// Fork is short for Snapshot().Fork().
+func (s *SerialEventScheduler) Fork() *SerialEventScheduler {
+	return s.Snapshot().Fork()
+}
+
+func (s *SerialEventSchedulerSnapshot) Now() time.Time {
+	return s.now
+}
+
+// Fork returns a new scheduler with the snapshot's state and Middleware, but
+// a timeline of its own. It starts without Metrics and Hooks, so that its
+// events aren't mixed into those of the scheduler it was forked from, and its
+// pending events aren't reported to either. Its events are
+// numbered on from the snapshot, so that forks give the same events the same
+// IDs.
+//
+// The actions and contexts of pending events are shared rather than copied:
+// an action keeping state, e.g. one resolved from a timing.ActionRegistry,
+// sees the events of all forks, and cancelling a context cancels its events
+// everywhere.
+func (s *SerialEventSchedulerSnapshot) Fork() *SerialEventScheduler {
+	fork := &SerialEventScheduler{
+		clock:           newClock(s.now),
+		Middleware:      slices.Clone(s.middleware),
+		eventGenerators: s.eventGenerators.clone(),
+	}
+	fork.eventIDs.Store(s.eventIDs)
+
+	return fork
+}
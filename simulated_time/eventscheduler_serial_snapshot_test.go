@@ -0,0 +1,125 @@
+package simulated_time
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/metamogul/timing"
+	"github.com/metamogul/timing/internal/recorder"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSerialEventScheduler_Fork(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	performed := make([]string, 0)
+	record := func(name string) timing.Action {
+		return timing.ActionFunc(func(ctx timing.ActionContext) {
+			performed = append(performed, ctx.Clock().Now().Format("15:04")+" "+name)
+		})
+	}
+
+	eventScheduler := NewSerialEventScheduler(now)
+	eventScheduler.PerformRepeatedly(record("report"), nil, time.Hour, context.Background())
+
+	var failing, succeeding *SerialEventScheduler
+	eventScheduler.PerformAfter(timing.ActionFunc(func(timing.ActionContext) {
+		failing = eventScheduler.Fork()
+		failing.PerformAfter(record("retry"), 30*time.Minute, context.Background())
+
+		succeeding = eventScheduler.Fork()
+		succeeding.PerformNow(record("done"), context.Background())
+	}), 2*time.Hour, context.Background())
+
+	eventScheduler.Forward(3 * time.Hour)
+	require.Equal(t, []string{"13:00 report", "14:00 report", "15:00 report"}, performed)
+
+	performed = performed[:0]
+	failing.Forward(time.Hour)
+	require.Equal(t, []string{"14:30 retry", "15:00 report"}, performed)
+
+	performed = performed[:0]
+	succeeding.Forward(time.Hour)
+	require.Equal(t, []string{"14:00 done", "15:00 report"}, performed)
+
+	require.Equal(t, now.Add(3*time.Hour), eventScheduler.Now())
+	require.Equal(t, now.Add(3*time.Hour), failing.Now())
+	require.Equal(t, now.Add(3*time.Hour), succeeding.Now())
+}
+
+func TestSerialEventScheduler_Fork_eventIDs(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	eventIDs := make([]uint64, 0)
+	eventScheduler := NewSerialEventScheduler(now)
+	eventScheduler.PerformRepeatedly(timing.ActionFunc(func(ctx timing.ActionContext) {
+		eventIDs = append(eventIDs, ctx.EventID())
+	}), nil, time.Hour, context.Background())
+
+	eventScheduler.Forward(2 * time.Hour)
+	fork := eventScheduler.Fork()
+
+	eventScheduler.Forward(time.Hour)
+	fork.Forward(time.Hour)
+
+	require.Equal(t, []uint64{1, 2, 3, 3}, eventIDs)
+}
+
+func TestSerialEventScheduler_Snapshot(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	performedAt := make([]time.Time, 0)
+	action := timing.ActionFunc(func(ctx timing.ActionContext) {
+		performedAt = append(performedAt, ctx.Clock().Now())
+	})
+
+	calls := 0
+	eventScheduler := NewSerialEventScheduler(now)
+	eventScheduler.Metrics = recorder.NewMetrics()
+	eventScheduler.Hooks = &timing.Hooks{}
+	eventScheduler.Middleware = []timing.ActionMiddleware{func(action timing.Action) timing.Action {
+		calls++
+		return action
+	}}
+	eventScheduler.PerformRepeatedly(action, nil, time.Hour, context.Background())
+	eventScheduler.Forward(90 * time.Minute)
+
+	snapshot := eventScheduler.Snapshot()
+	require.Equal(t, now.Add(90*time.Minute), snapshot.Now())
+
+	eventScheduler.Forward(2 * time.Hour)
+	require.Equal(t, []time.Time{now.Add(time.Hour), now.Add(2 * time.Hour), now.Add(3 * time.Hour)}, performedAt)
+
+	for range 2 {
+		performedAt = performedAt[:0]
+
+		fork := snapshot.Fork()
+		require.Nil(t, fork.Metrics)
+		require.Nil(t, fork.Hooks)
+		require.Equal(t, now.Add(90*time.Minute), fork.Now())
+
+		fork.Forward(time.Hour)
+		require.Equal(t, []time.Time{now.Add(2 * time.Hour)}, performedAt)
+	}
+
+	require.Equal(t, 5, calls)
+}
+
+func TestSerialEventScheduler_Snapshot_notCloneable(t *testing.T) {
+	t.Parallel()
+
+	generator := NewMockEventGenerator(t)
+	generator.EXPECT().Finished().Return(false)
+
+	eventScheduler := NewSerialEventScheduler(time.Time{})
+	eventScheduler.AddGenerator(generator)
+
+	require.PanicsWithValue(t, ErrEventGeneratorNotCloneable, func() { eventScheduler.Snapshot() })
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"slices"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/metamogul/timing"
+	"github.com/metamogul/timing/internal/recorder"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -280,3 +282,323 @@ func TestSerialEventScheduler_AddGenerator(t *testing.T) {
 
 	require.Len(t, s.eventGenerators.activeGenerators, 1)
 }
+
+func TestSerialEventScheduler_Metrics(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	metrics := recorder.NewMetrics()
+
+	eventSchedulerUnderTest := NewSerialEventScheduler(now)
+	eventSchedulerUnderTest.Metrics = metrics
+
+	repeatedCtx, cancelRepeated := context.WithCancel(timing.WithJobName(context.Background(), "repeated"))
+
+	repeatedAction := timing.NewMockAction(t)
+	repeatedAction.EXPECT().
+		Perform(mock.Anything).
+		Once()
+	eventSchedulerUnderTest.PerformRepeatedly(repeatedAction, nil, 30*time.Second, repeatedCtx)
+
+	cancellingAction := timing.NewMockAction(t)
+	cancellingAction.EXPECT().
+		Perform(mock.Anything).
+		Run(func(timing.ActionContext) { cancelRepeated() }).
+		Once()
+	eventSchedulerUnderTest.PerformAfter(cancellingAction, 45*time.Second, timing.WithJobName(context.Background(), "single"))
+
+	eventSchedulerUnderTest.Forward(2 * time.Minute)
+
+	require.Equal(t, map[string]int{"repeated": 1, "single": 1}, metrics.Scheduled)
+	require.Equal(t, map[string]int{"repeated": 1}, metrics.Cancelled)
+	require.Equal(t, map[string]int{"repeated": 1, "single": 1}, metrics.Started)
+	require.Equal(t, map[string]int{"repeated": 1, "single": 1}, metrics.Finished)
+	require.Equal(t, []time.Duration{0, 0}, metrics.Lateness)
+	require.Equal(t, []time.Duration{0, 0}, metrics.Durations)
+}
+
+func TestSerialEventScheduler_Hooks(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	hooks := &recordingHooks{}
+
+	eventSchedulerUnderTest := NewSerialEventScheduler(now)
+	eventSchedulerUnderTest.Hooks = hooks.hooks()
+
+	cancelledCtx, cancel := context.WithCancel(timing.WithJobName(context.Background(), "cancelled"))
+
+	eventSchedulerUnderTest.PerformAfter(timing.ActionFunc(func(timing.ActionContext) { cancel() }), time.Minute, timing.WithJobName(context.Background(), "single"))
+	eventSchedulerUnderTest.PerformRepeatedly(timing.ActionFunc(func(timing.ActionContext) {}), ptr(now.Add(3*time.Minute)), time.Minute, timing.WithJobName(context.Background(), "repeated"))
+	eventSchedulerUnderTest.PerformAfter(timing.NewMockAction(t), 5*time.Minute, cancelledCtx)
+
+	eventSchedulerUnderTest.Forward(3 * time.Minute)
+
+	require.Equal(t, []string{
+		"scheduled single 12:01",
+		"scheduled repeated 12:01",
+		"scheduled cancelled 12:05",
+		"finished single 12:01",
+		"advanced single 12:01",
+		"before single 12:01",
+		"after single 12:01",
+		"cancelled cancelled 12:05",
+		"before repeated 12:01",
+		"after repeated 12:01",
+		"finished repeated 12:02",
+		"advanced repeated 12:02",
+		"before repeated 12:02",
+		"after repeated 12:02",
+		"advanced  12:03",
+	}, hooks.recorded())
+}
+
+func TestSerialEventScheduler_Middleware(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	calls := make([]string, 0)
+	middleware := func(name string) timing.ActionMiddleware {
+		return func(action timing.Action) timing.Action {
+			return timing.ActionFunc(func(ctx timing.ActionContext) {
+				calls = append(calls, name+":"+ctx.Metadata().JobName)
+				action.Perform(ctx)
+			})
+		}
+	}
+
+	eventSchedulerUnderTest := NewSerialEventScheduler(now)
+	eventSchedulerUnderTest.Middleware = []timing.ActionMiddleware{middleware("scheduler")}
+
+	ctx := timing.WithMiddleware(timing.WithJobName(context.Background(), "job"), middleware("job"))
+
+	mockAction := timing.NewMockAction(t)
+	mockAction.EXPECT().
+		Perform(mock.Anything).
+		Run(func(ctx timing.ActionContext) {
+			calls = append(calls, "action")
+			require.Equal(t, timing.EventMetadata{ScheduledTime: now.Add(time.Minute), JobName: "job", EventID: 1, Occurrence: 1}, ctx.Metadata())
+		}).
+		Once()
+
+	eventSchedulerUnderTest.PerformAfter(mockAction, time.Minute, ctx)
+	eventSchedulerUnderTest.Forward(time.Minute)
+
+	require.Equal(t, []string{"scheduler:job", "job:job", "action"}, calls)
+}
+
+func TestSerialEventScheduler_ActionContext(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	eventSchedulerUnderTest := NewSerialEventScheduler(now)
+
+	performed := make([]timing.EventMetadata, 0)
+	record := timing.ActionFunc(func(ctx timing.ActionContext) {
+		performed = append(performed, timing.EventMetadata{
+			ScheduledTime: ctx.ScheduledTime(),
+			JobName:       ctx.JobName(),
+			EventID:       ctx.EventID(),
+			Occurrence:    ctx.Occurrence(),
+		})
+	})
+
+	eventSchedulerUnderTest.PerformRepeatedly(timing.ActionFunc(func(ctx timing.ActionContext) {
+		record(ctx)
+		require.Same(t, eventSchedulerUnderTest, ctx.Scheduler())
+
+		if ctx.Occurrence() == 2 {
+			ctx.Scheduler().PerformAfter(record, 30*time.Second, timing.WithJobName(context.Background(), "follow-up"))
+		}
+	}), ptr(now.Add(4*time.Minute)), time.Minute, timing.WithJobName(context.Background(), "job"))
+
+	eventSchedulerUnderTest.Forward(5 * time.Minute)
+
+	require.Equal(t, []timing.EventMetadata{
+		{ScheduledTime: now.Add(time.Minute), JobName: "job", EventID: 1, Occurrence: 1},
+		{ScheduledTime: now.Add(2 * time.Minute), JobName: "job", EventID: 2, Occurrence: 2},
+		{ScheduledTime: now.Add(2*time.Minute + 30*time.Second), JobName: "follow-up", EventID: 3, Occurrence: 1},
+		{ScheduledTime: now.Add(3 * time.Minute), JobName: "job", EventID: 4, Occurrence: 3},
+	}, performed)
+}
+
+func TestSerialEventScheduler_ClockFrom(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	eventSchedulerUnderTest := NewSerialEventScheduler(now)
+
+	var seen time.Time
+	eventSchedulerUnderTest.PerformAfter(timing.ActionFunc(func(ctx timing.ActionContext) {
+		derived, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		seen = timing.ClockFrom(derived).Now()
+	}), time.Minute, context.Background())
+
+	eventSchedulerUnderTest.Forward(time.Hour)
+
+	require.Equal(t, now.Add(time.Minute), seen)
+}
+
+func TestSerialEventScheduler_WithTimeout(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	eventSchedulerUnderTest := NewSerialEventScheduler(now)
+
+	ctx, cancel := eventSchedulerUnderTest.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	deadline, ok := ctx.Deadline()
+	require.True(t, ok)
+	require.Equal(t, now.Add(time.Minute), deadline)
+
+	eventSchedulerUnderTest.Forward(59 * time.Second)
+	require.NoError(t, ctx.Err())
+
+	eventSchedulerUnderTest.Forward(time.Second)
+	require.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+
+	ctx, cancel = eventSchedulerUnderTest.WithDeadline(context.Background(), now.Add(2*time.Minute))
+	cancel()
+	require.ErrorIs(t, ctx.Err(), context.Canceled)
+	require.True(t, eventSchedulerUnderTest.eventGenerators.Finished())
+}
+
+// TestSerialEventScheduler_concurrentScheduling schedules events from helper
+// goroutines while the test forwards the scheduler. It is meant to be run
+// with the race detector.
+func TestSerialEventScheduler_concurrentScheduling(t *testing.T) {
+	t.Parallel()
+
+	const (
+		helpers = 20
+		events  = 50
+	)
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	eventSchedulerUnderTest := NewSerialEventScheduler(now)
+
+	inFlight := atomic.Int32{}
+	performed := atomic.Int32{}
+	countingAction := timing.ActionFunc(func(ctx timing.ActionContext) {
+		require.Equal(t, int32(1), inFlight.Add(1), "actions performed concurrently")
+		defer inFlight.Add(-1)
+
+		require.Equal(t, ctx.Clock().Now(), eventSchedulerUnderTest.Now())
+		performed.Add(1)
+	})
+
+	wg := sync.WaitGroup{}
+	for range helpers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range events {
+				eventSchedulerUnderTest.PerformAfter(countingAction, time.Duration(i)*time.Millisecond, context.Background())
+			}
+		}()
+	}
+
+	for range events {
+		eventSchedulerUnderTest.Forward(time.Millisecond)
+	}
+	wg.Wait()
+	eventSchedulerUnderTest.Forward(time.Second)
+
+	require.Equal(t, int32(helpers*events), performed.Load())
+}
+
+// TestSerialEventScheduler_concurrentCancelling cancels events from helper
+// goroutines while they are scheduled and the test forwards the scheduler. It
+// is meant to be run with the race detector.
+func TestSerialEventScheduler_concurrentCancelling(t *testing.T) {
+	t.Parallel()
+
+	const (
+		helpers = 20
+		events  = 50
+	)
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	eventSchedulerUnderTest := NewSerialEventScheduler(now)
+
+	performed := atomic.Int32{}
+	countingAction := timing.ActionFunc(func(timing.ActionContext) {
+		performed.Add(1)
+	})
+
+	wg := sync.WaitGroup{}
+	for range helpers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range events {
+				ctx, cancel := context.WithCancel(context.Background())
+				eventSchedulerUnderTest.PerformAfter(countingAction, time.Duration(i)*time.Millisecond, ctx)
+				eventSchedulerUnderTest.PerformRepeatedly(countingAction, nil, time.Millisecond, ctx)
+				if i%2 == 0 {
+					cancel()
+				} else {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						cancel()
+					}()
+				}
+			}
+		}()
+	}
+
+	for range events {
+		eventSchedulerUnderTest.Forward(time.Millisecond)
+	}
+	wg.Wait()
+	eventSchedulerUnderTest.Forward(time.Second)
+
+	require.True(t, eventSchedulerUnderTest.eventGenerators.Finished())
+}
+
+func TestSerialEventScheduler_Forward_performsOnCallingGoroutine(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	eventSchedulerUnderTest := NewSerialEventScheduler(now)
+
+	forwarding := false
+	performed := make(chan struct{})
+
+	go eventSchedulerUnderTest.PerformAfter(timing.ActionFunc(func(timing.ActionContext) {
+		// Written without synchronization by the goroutine calling Forward,
+		// so the race detector reports any other goroutine.
+		require.True(t, forwarding)
+		close(performed)
+	}), time.Second, context.Background())
+
+	require.Eventually(t, func() bool {
+		eventSchedulerUnderTest.eventGeneratorsMu.Lock()
+		defer eventSchedulerUnderTest.eventGeneratorsMu.Unlock()
+
+		return !eventSchedulerUnderTest.eventGenerators.Finished()
+	}, time.Second, time.Millisecond)
+
+	forwarding = true
+	eventSchedulerUnderTest.Forward(time.Second)
+	forwarding = false
+
+	select {
+	case <-performed:
+	default:
+		t.Fatal("action not performed before Forward returned")
+	}
+}
@@ -0,0 +1,57 @@
+package simulated_time
+
+import (
+	"context"
+	"time"
+
+	"github.com/metamogul/timing"
+)
+
+// TimingWheelEventScheduler schedules onto another simulated scheduler with
+// the tick rounding of system.NewTimingWheelEventScheduler, so tests observe
+// the same coarse firing times as production. Time is still advanced through
+// the wrapped scheduler.
+type TimingWheelEventScheduler struct {
+	GeneratorScheduler
+
+	tick time.Duration
+}
+
+func NewTimingWheelEventScheduler(scheduler GeneratorScheduler, tick time.Duration) *TimingWheelEventScheduler {
+	if scheduler == nil {
+		panic("scheduler can't be nil")
+	}
+
+	if tick <= 0 {
+		panic("tick must be greater than zero")
+	}
+
+	return &TimingWheelEventScheduler{
+		GeneratorScheduler: scheduler,
+		tick:               tick,
+	}
+}
+
+func (t *TimingWheelEventScheduler) PerformNow(action timing.Action, ctx context.Context) {
+	t.GeneratorScheduler.PerformNow(action, t.withScheduler(ctx))
+}
+
+func (t *TimingWheelEventScheduler) PerformAfter(action timing.Action, interval time.Duration, ctx context.Context) {
+	t.AddGenerator(newTimingWheelEventGenerator(action, t.Now().Add(interval), 0, nil, t.tick, t.withScheduler(ctx)))
+}
+
+func (t *TimingWheelEventScheduler) PerformRepeatedly(action timing.Action, until *time.Time, interval time.Duration, ctx context.Context) {
+	if interval <= 0 {
+		panic("interval must be greater than zero")
+	}
+
+	t.AddGenerator(newTimingWheelEventGenerator(action, t.Now().Add(interval), interval, until, t.tick, t.withScheduler(ctx)))
+}
+
+func (t *TimingWheelEventScheduler) withScheduler(ctx context.Context) context.Context {
+	if timing.SchedulerFrom(ctx) != nil {
+		return ctx
+	}
+
+	return timing.WithScheduler(ctx, t)
+}
@@ -0,0 +1,143 @@
+package simulated_time
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/metamogul/timing"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTimingWheelEventScheduler(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	require.Panics(t, func() {
+		_ = NewTimingWheelEventScheduler(nil, time.Millisecond)
+	})
+
+	require.Panics(t, func() {
+		_ = NewTimingWheelEventScheduler(NewSerialEventScheduler(now), 0)
+	})
+
+	eventSchedulerUnderTest := NewTimingWheelEventScheduler(NewSerialEventScheduler(now), time.Millisecond)
+	require.NotNil(t, eventSchedulerUnderTest)
+	require.Equal(t, now, eventSchedulerUnderTest.Now())
+}
+
+func TestTimingWheelEventScheduler_PerformAfter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	scheduler := NewSerialEventScheduler(now)
+	eventSchedulerUnderTest := NewTimingWheelEventScheduler(scheduler, 10*time.Millisecond)
+
+	eventTimes := make([]time.Time, 0)
+
+	mockAction := timing.NewMockAction(t)
+	mockAction.EXPECT().
+		Perform(mock.Anything).
+		Run(func(ctx timing.ActionContext) {
+			eventTimes = append(eventTimes, ctx.Clock().Now())
+		}).
+		Twice()
+
+	eventSchedulerUnderTest.PerformAfter(mockAction, 3*time.Millisecond, context.Background())
+	eventSchedulerUnderTest.PerformAfter(mockAction, 20*time.Millisecond, context.Background())
+
+	scheduler.Forward(time.Second)
+
+	require.Equal(t, []time.Time{now.Add(10 * time.Millisecond), now.Add(20 * time.Millisecond)}, eventTimes)
+}
+
+func TestTimingWheelEventScheduler_PerformRepeatedly(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	scheduler := NewSerialEventScheduler(now)
+	eventSchedulerUnderTest := NewTimingWheelEventScheduler(scheduler, 10*time.Millisecond)
+
+	require.Panics(t, func() {
+		eventSchedulerUnderTest.PerformRepeatedly(timing.NewMockAction(t), nil, 0, context.Background())
+	})
+
+	eventTimes := make([]time.Time, 0)
+
+	mockAction := timing.NewMockAction(t)
+	mockAction.EXPECT().
+		Perform(mock.Anything).
+		Run(func(ctx timing.ActionContext) {
+			eventTimes = append(eventTimes, ctx.Clock().Now())
+		}).
+		Times(3)
+
+	eventSchedulerUnderTest.PerformRepeatedly(mockAction, ptr(now.Add(60*time.Millisecond)), 15*time.Millisecond, context.Background())
+
+	scheduler.Forward(time.Second)
+
+	require.Equal(t, []time.Time{
+		now.Add(20 * time.Millisecond),
+		now.Add(30 * time.Millisecond),
+		now.Add(50 * time.Millisecond),
+	}, eventTimes)
+}
+
+func TestTimingWheelEventScheduler_Scheduler(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 12, 27, 12, 0, 0, 0, time.UTC)
+
+	scheduler := NewSerialEventScheduler(now)
+	timingWheelScheduler := NewTimingWheelEventScheduler(scheduler, time.Second)
+	calendarScheduler := timing.CalendarEventScheduler{
+		EventScheduler: timingWheelScheduler,
+		Calendar:       timing.NewCalendar(nil),
+	}
+
+	seen := make([]timing.EventScheduler, 0)
+	action := timing.ActionFunc(func(ctx timing.ActionContext) {
+		seen = append(seen, ctx.Scheduler())
+	})
+
+	timingWheelScheduler.PerformNow(action, context.Background())
+	timingWheelScheduler.PerformAfter(action, time.Second, context.Background())
+	timingWheelScheduler.PerformRepeatedly(action, ptr(now.Add(3*time.Second)), 2*time.Second, context.Background())
+	calendarScheduler.PerformAfter(action, 4*time.Second, context.Background())
+
+	scheduler.Forward(5 * time.Second)
+
+	require.Len(t, seen, 4)
+	for _, eventScheduler := range seen[:3] {
+		require.Same(t, timingWheelScheduler, eventScheduler)
+	}
+	require.Equal(t, calendarScheduler, seen[3])
+}
+
+func TestTimingWheelEventScheduler_Scheduler_rescheduling(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 12, 27, 12, 0, 0, 0, time.UTC)
+
+	scheduler := NewSerialEventScheduler(now)
+	timingWheelScheduler := NewTimingWheelEventScheduler(scheduler, time.Second)
+
+	performances := 0
+	var action timing.ActionFunc
+	action = func(ctx timing.ActionContext) {
+		performances++
+		require.Same(t, timingWheelScheduler, ctx.Scheduler())
+		require.Empty(t, timing.MiddlewareFrom(ctx))
+
+		ctx.Scheduler().PerformAfter(action, time.Second, ctx)
+	}
+
+	timingWheelScheduler.PerformAfter(action, time.Second, context.Background())
+	scheduler.Forward(100 * time.Second)
+
+	require.Equal(t, 100, performances)
+}
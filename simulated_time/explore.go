@@ -0,0 +1,154 @@
+package simulated_time
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+)
+
+// interleaver draws the ordering choices of a seeded AsyncEventScheduler.
+type interleaver struct {
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+func newInterleaver(seed uint64) *interleaver {
+	return &interleaver{
+		rng: rand.New(rand.NewPCG(seed, seed)),
+	}
+}
+
+func (i *interleaver) intN(n int) int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return i.rng.IntN(n)
+}
+
+// NewSeededAsyncEventScheduler returns an AsyncEventScheduler that performs
+// its actions one at a time, each still in its own goroutine, and lets seed
+// pick the order of events due at the same time. The same seed therefore
+// always results in the same interleaving.
+//
+// Only the order of same-time events is explored. Since every action returns
+// before the next one starts, a SchedulingAction's DoneSchedulingNewEvents
+// doesn't let the event loop run on early, and the interleavings of actions
+// running concurrently, as they do without a seed, aren't covered.
+//
+// Actions performed by a seeded scheduler must not wait for each other, and
+// a panicking action is handed on to the goroutine calling Forward.
+func NewSeededAsyncEventScheduler(now time.Time, seed uint64) *AsyncEventScheduler {
+	scheduler := NewAsyncEventScheduler(now)
+	scheduler.interleaver = newInterleaver(seed)
+
+	return scheduler
+}
+
+// Scenario is run by Explore and Replay. It should create its scheduler with
+// NewSeededAsyncEventScheduler and the given seed, and report failures to t,
+// which works with testify's assert and require.
+type Scenario func(t *ExplorationT, seed uint64)
+
+// ExplorationT collects the failures of a single run of a Scenario.
+type ExplorationT struct {
+	mu       sync.Mutex
+	failed   bool
+	messages []string
+}
+
+func (e *ExplorationT) Errorf(format string, args ...any) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.failed = true
+	e.messages = append(e.messages, fmt.Sprintf(format, args...))
+}
+
+func (e *ExplorationT) Logf(format string, args ...any) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.messages = append(e.messages, fmt.Sprintf(format, args...))
+}
+
+func (e *ExplorationT) Fail() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.failed = true
+}
+
+// FailNow marks the run as failed and stops it. Like testing.T.FailNow, it
+// must be called from the scenario's goroutine.
+func (e *ExplorationT) FailNow() {
+	e.Fail()
+	runtime.Goexit()
+}
+
+func (e *ExplorationT) Failed() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.failed
+}
+
+func (e *ExplorationT) Helper() {}
+
+func (e *ExplorationT) String() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return strings.Join(e.messages, "\n")
+}
+
+func runScenario(scenario Scenario, seed uint64) *ExplorationT {
+	e := &ExplorationT{}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer func() {
+			if r := recover(); r != nil {
+				e.Errorf("panic: %v\n%s", r, debug.Stack())
+			}
+		}()
+
+		scenario(e, seed)
+	}()
+	<-done
+
+	return e
+}
+
+// FatalReporter is the part of testing.TB used by Explore and Replay.
+type FatalReporter interface {
+	Helper()
+	Fatalf(format string, args ...any)
+}
+
+// Explore runs scenario once for each of the seeds 0 to runs-1, and thus
+// with up to runs orders of its same-time events, and fails t with the first
+// seed whose run failed, which Replay can then run again.
+func Explore(t FatalReporter, runs int, scenario Scenario) {
+	t.Helper()
+
+	for seed := range uint64(runs) {
+		if e := runScenario(scenario, seed); e.Failed() {
+			t.Fatalf("interleaving with seed %d failed, replay it with Replay(t, %d, scenario):\n%s", seed, seed, e)
+			return
+		}
+	}
+}
+
+// Replay runs scenario with the interleaving of seed.
+func Replay(t FatalReporter, seed uint64, scenario Scenario) {
+	t.Helper()
+
+	if e := runScenario(scenario, seed); e.Failed() {
+		t.Fatalf("interleaving with seed %d failed:\n%s", seed, e)
+	}
+}
@@ -0,0 +1,205 @@
+package simulated_time
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/metamogul/timing"
+	"github.com/stretchr/testify/require"
+)
+
+// fatalRecorder records Fatalf instead of stopping the test.
+type fatalRecorder struct {
+	fatal string
+}
+
+func (f *fatalRecorder) Helper() {}
+
+func (f *fatalRecorder) Fatalf(format string, args ...any) {
+	f.fatal = fmt.Sprintf(format, args...)
+}
+
+// orderScenario fails whenever the second of two same-time events is
+// started first.
+func orderScenario(t *ExplorationT, seed uint64) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	eventScheduler := NewSeededAsyncEventScheduler(now, seed)
+
+	mu := sync.Mutex{}
+	order := make([]string, 0)
+	recordingAction := func(name string) timing.Action {
+		return NewSchedulingAction(timing.ActionFunc(func(ctx timing.ActionContext) {
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+
+			ctx.DoneSchedulingNewEvents()
+		}))
+	}
+
+	eventScheduler.PerformAfter(recordingAction("first"), time.Second, context.Background())
+	eventScheduler.PerformAfter(recordingAction("second"), time.Second, context.Background())
+	eventScheduler.Forward(time.Second)
+
+	require.Equal(t, []string{"first", "second"}, order)
+}
+
+func TestExplore(t *testing.T) {
+	t.Parallel()
+
+	recorder := &fatalRecorder{}
+	Explore(recorder, 100, orderScenario)
+
+	require.Contains(t, recorder.fatal, "interleaving with seed")
+
+	var seed uint64
+	_, err := fmt.Sscanf(recorder.fatal, "interleaving with seed %d failed", &seed)
+	require.NoError(t, err)
+
+	for range 10 {
+		replayRecorder := &fatalRecorder{}
+		Replay(replayRecorder, seed, orderScenario)
+		require.Contains(t, replayRecorder.fatal, fmt.Sprintf("interleaving with seed %d failed", seed))
+	}
+}
+
+func TestExplore_passing(t *testing.T) {
+	t.Parallel()
+
+	Explore(t, 20, func(t *ExplorationT, seed uint64) {
+		now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+		eventScheduler := NewSeededAsyncEventScheduler(now, seed)
+
+		mu := sync.Mutex{}
+		performed := 0
+		for range 5 {
+			eventScheduler.PerformAfter(timing.ActionFunc(func(timing.ActionContext) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				performed++
+			}), time.Second, context.Background())
+		}
+		eventScheduler.Forward(time.Second)
+
+		require.Equal(t, 5, performed)
+	})
+}
+
+func TestExplore_panic(t *testing.T) {
+	t.Parallel()
+
+	recorder := &fatalRecorder{}
+	Explore(recorder, 10, func(t *ExplorationT, seed uint64) {
+		now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+		eventScheduler := NewSeededAsyncEventScheduler(now, seed)
+		eventScheduler.PerformAfter(timing.ActionFunc(func(timing.ActionContext) {
+			panic("action failed")
+		}), time.Second, context.Background())
+		eventScheduler.Forward(time.Second)
+	})
+
+	require.Contains(t, recorder.fatal, "interleaving with seed 0 failed")
+	require.Contains(t, recorder.fatal, "panic: action failed")
+}
+
+func TestNewSeededAsyncEventScheduler_deterministic(t *testing.T) {
+	t.Parallel()
+
+	performOrder := func(seed uint64) []int {
+		now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+		eventScheduler := NewSeededAsyncEventScheduler(now, seed)
+
+		mu := sync.Mutex{}
+		order := make([]int, 0)
+		for i := range 10 {
+			eventScheduler.PerformAfter(timing.ActionFunc(func(timing.ActionContext) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				order = append(order, i)
+			}), time.Second, context.Background())
+		}
+		eventScheduler.Forward(time.Second)
+
+		return order
+	}
+
+	for seed := range uint64(10) {
+		order := performOrder(seed)
+		require.Len(t, order, 10)
+
+		for range 10 {
+			require.Equal(t, order, performOrder(seed))
+		}
+	}
+}
+
+func TestNewSeededAsyncEventScheduler_schedulingActionsRunToCompletion(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	for seed := range uint64(10) {
+		eventScheduler := NewSeededAsyncEventScheduler(now, seed)
+
+		mu := sync.Mutex{}
+		order := make([]string, 0)
+		record := func(name string) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			order = append(order, name)
+		}
+
+		eventScheduler.PerformAfter(NewSchedulingAction(timing.ActionFunc(func(ctx timing.ActionContext) {
+			record("started")
+			ctx.DoneSchedulingNewEvents()
+			time.Sleep(time.Millisecond)
+			record("returned")
+		})), time.Second, context.Background())
+		eventScheduler.PerformAfter(timing.ActionFunc(func(timing.ActionContext) {
+			record("other")
+		}), time.Second, context.Background())
+		eventScheduler.Forward(time.Second)
+
+		require.Contains(t, [][]string{
+			{"started", "returned", "other"},
+			{"other", "started", "returned"},
+		}, order, "seed %d", seed)
+	}
+}
+
+func TestReplay_passing(t *testing.T) {
+	t.Parallel()
+
+	passingSeed := uint64(0)
+	for ; runScenario(orderScenario, passingSeed).Failed(); passingSeed++ {
+	}
+
+	recorder := &fatalRecorder{}
+	Replay(recorder, passingSeed, orderScenario)
+	require.Empty(t, recorder.fatal)
+}
+
+func TestExplorationT(t *testing.T) {
+	t.Parallel()
+
+	e := runScenario(func(t *ExplorationT, _ uint64) {
+		t.Logf("logged %d", 1)
+		require.False(t, t.Failed())
+		t.Errorf("failed %s", "softly")
+		t.FailNow()
+		t.Errorf("unreachable")
+	}, 0)
+
+	require.True(t, e.Failed())
+	require.Equal(t, "logged 1\nfailed softly", e.String())
+}
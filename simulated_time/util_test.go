@@ -1,5 +1,46 @@
 package simulated_time
 
+import (
+	"fmt"
+	"github.com/metamogul/timing"
+	"slices"
+	"sync"
+)
+
 func ptr[T any](t T) *T {
 	return &t
 }
+
+// recordingHooks records the hooks called, as the hook's name, the job and
+// the time of day.
+type recordingHooks struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *recordingHooks) hooks() *timing.Hooks {
+	record := func(hook string) func(timing.EventMetadata) {
+		return func(metadata timing.EventMetadata) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+
+			r.calls = append(r.calls, fmt.Sprintf("%s %s %s", hook, metadata.JobName, metadata.ScheduledTime.Format("15:04")))
+		}
+	}
+
+	return &timing.Hooks{
+		OnScheduled:         record("scheduled"),
+		OnBeforePerform:     record("before"),
+		OnAfterPerform:      record("after"),
+		OnCancelled:         record("cancelled"),
+		OnGeneratorFinished: record("finished"),
+		OnClockAdvanced:     record("advanced"),
+	}
+}
+
+func (r *recordingHooks) recorded() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Clone(r.calls)
+}
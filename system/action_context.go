@@ -3,17 +3,29 @@ package system
 import (
 	"context"
 	"github.com/metamogul/timing"
+	"time"
 )
 
 type actionContext struct {
 	context.Context
-	clock timing.Clock
+	// values is Context carrying clock, for ClockFrom.
+	values    context.Context
+	clock     timing.Clock
+	metadata  timing.EventMetadata
+	scheduler timing.EventScheduler
 }
 
-func newActionContext(ctx context.Context, clock timing.Clock) *actionContext {
+func newActionContext(ctx context.Context, clock timing.Clock, metadata timing.EventMetadata, scheduler timing.EventScheduler) *actionContext {
+	if override := timing.SchedulerFrom(ctx); override != nil {
+		scheduler = override
+	}
+
 	return &actionContext{
-		Context: ctx,
-		clock:   clock,
+		Context:   ctx,
+		values:    timing.WithClock(ctx, clock),
+		clock:     clock,
+		metadata:  metadata,
+		scheduler: scheduler,
 	}
 }
 
@@ -23,11 +35,35 @@ func (a *actionContext) Clock() timing.Clock {
 
 func (a *actionContext) DoneSchedulingNewEvents() { /*Noop*/ }
 
+func (a *actionContext) Metadata() timing.EventMetadata {
+	return a.metadata
+}
+
+func (a *actionContext) ScheduledTime() time.Time {
+	return a.metadata.ScheduledTime
+}
+
+func (a *actionContext) EventID() uint64 {
+	return a.metadata.EventID
+}
+
+func (a *actionContext) JobName() string {
+	return a.metadata.JobName
+}
+
+func (a *actionContext) Occurrence() int {
+	return a.metadata.Occurrence
+}
+
+func (a *actionContext) Scheduler() timing.EventScheduler {
+	return a.scheduler
+}
+
 func (a *actionContext) Value(key any) any {
 	switch key {
 	case timing.ActionContextClockKey:
 		return a.clock
 	default:
-		return a.Context.Value(key)
+		return a.values.Value(key)
 	}
 }
@@ -5,6 +5,7 @@ import (
 	"github.com/metamogul/timing"
 	"github.com/stretchr/testify/require"
 	"testing"
+	"time"
 )
 
 func TestNewActionContext(t *testing.T) {
@@ -13,7 +14,7 @@ func TestNewActionContext(t *testing.T) {
 	clock := Clock{}
 	ctx := context.Background()
 
-	actionContextUnderTest := newActionContext(ctx, clock)
+	actionContextUnderTest := newActionContext(ctx, clock, timing.EventMetadata{}, nil)
 	require.NotNil(t, actionContextUnderTest)
 	require.NotNil(t, actionContextUnderTest.Context)
 	require.NotNil(t, actionContextUnderTest.clock)
@@ -24,7 +25,7 @@ func TestActionContext_Clock(t *testing.T) {
 
 	clock := Clock{}
 
-	actionContextUnderTest := newActionContext(context.Background(), clock)
+	actionContextUnderTest := newActionContext(context.Background(), clock, timing.EventMetadata{}, nil)
 	gotClock := actionContextUnderTest.Clock()
 	require.Equal(t, clock, gotClock)
 }
@@ -32,7 +33,7 @@ func TestActionContext_Clock(t *testing.T) {
 func TestActionContext_DoneSchedulingNewEvents(t *testing.T) {
 	t.Parallel()
 
-	actionContextUnderTest := newActionContext(context.Background(), Clock{})
+	actionContextUnderTest := newActionContext(context.Background(), Clock{}, timing.EventMetadata{}, nil)
 	actionContextUnderTest.DoneSchedulingNewEvents()
 }
 
@@ -41,15 +42,41 @@ func TestActionContext_Value_Clock(t *testing.T) {
 
 	clock := Clock{}
 
-	actionContextUnderTest := newActionContext(context.Background(), clock)
+	actionContextUnderTest := newActionContext(context.Background(), clock, timing.EventMetadata{}, nil)
 	gotClock := actionContextUnderTest.Value(timing.ActionContextClockKey)
 	require.Equal(t, clock, gotClock)
+	require.Equal(t, clock, timing.ClockFrom(actionContextUnderTest))
 }
 
 func TestActionContext_Value_Default(t *testing.T) {
 	t.Parallel()
 
-	actionContextUnderTest := newActionContext(context.Background(), Clock{})
+	actionContextUnderTest := newActionContext(context.Background(), Clock{}, timing.EventMetadata{}, nil)
 	gotValue := actionContextUnderTest.Value("someNoneExistentKey")
 	require.Nil(t, gotValue)
 }
+
+func TestActionContext_Metadata(t *testing.T) {
+	t.Parallel()
+
+	metadata := timing.EventMetadata{ScheduledTime: time.Now(), JobName: "job", EventID: 3, Occurrence: 2}
+	scheduler := &EventScheduler{}
+
+	actionContextUnderTest := newActionContext(context.Background(), Clock{}, metadata, scheduler)
+	require.Equal(t, metadata, actionContextUnderTest.Metadata())
+	require.Equal(t, metadata.ScheduledTime, actionContextUnderTest.ScheduledTime())
+	require.Equal(t, uint64(3), actionContextUnderTest.EventID())
+	require.Equal(t, "job", actionContextUnderTest.JobName())
+	require.Equal(t, 2, actionContextUnderTest.Occurrence())
+	require.Same(t, scheduler, actionContextUnderTest.Scheduler())
+}
+
+func TestActionContext_Scheduler_override(t *testing.T) {
+	t.Parallel()
+
+	override := &EventScheduler{}
+	ctx := timing.WithScheduler(context.Background(), override)
+
+	actionContextUnderTest := newActionContext(ctx, Clock{}, timing.EventMetadata{}, &EventScheduler{})
+	require.Same(t, override, actionContextUnderTest.Scheduler())
+}
@@ -0,0 +1,26 @@
+package system
+
+import (
+	"time"
+)
+
+// FixedClock always returns the same time.
+type FixedClock time.Time
+
+func (f FixedClock) Now() time.Time {
+	return time.Time(f)
+}
+
+// OffsetClock returns real time shifted by its duration, e.g. to let a
+// staging environment pretend it is the end of the month.
+type OffsetClock time.Duration
+
+// NewOffsetClock returns an OffsetClock that currently shows now and keeps
+// advancing in real time from there.
+func NewOffsetClock(now time.Time) OffsetClock {
+	return OffsetClock(time.Until(now))
+}
+
+func (o OffsetClock) Now() time.Time {
+	return time.Now().Add(time.Duration(o))
+}
@@ -0,0 +1,46 @@
+package system
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFixedClock_Now(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 31, 23, 59, 0, 0, time.UTC)
+
+	clock := FixedClock(now)
+	require.Equal(t, now, clock.Now())
+
+	time.Sleep(time.Millisecond)
+	require.Equal(t, now, clock.Now())
+}
+
+func TestOffsetClock_Now(t *testing.T) {
+	t.Parallel()
+
+	clock := OffsetClock(24 * time.Hour)
+
+	before := time.Now().Add(24 * time.Hour)
+	got := clock.Now()
+	after := time.Now().Add(24 * time.Hour)
+
+	require.False(t, got.Before(before))
+	require.False(t, got.After(after))
+}
+
+func TestNewOffsetClock(t *testing.T) {
+	t.Parallel()
+
+	monthEnd := time.Date(2024, 1, 31, 23, 59, 0, 0, time.UTC)
+
+	clock := NewOffsetClock(monthEnd)
+
+	require.WithinDuration(t, monthEnd, clock.Now(), time.Second)
+
+	time.Sleep(10 * time.Millisecond)
+	require.True(t, clock.Now().After(monthEnd))
+}
@@ -0,0 +1,12 @@
+package system
+
+import (
+	"context"
+	"time"
+)
+
+type engine interface {
+	performNow(task func(scheduledTime time.Time), ctx context.Context)
+	performAfter(task func(scheduledTime time.Time), duration time.Duration, ctx context.Context)
+	performRepeatedly(task func(scheduledTime time.Time), until *time.Time, interval time.Duration, ctx context.Context)
+}
@@ -0,0 +1,235 @@
+package system
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+type dispatcherTimer struct {
+	deadline time.Time
+	interval time.Duration
+	until    *time.Time
+
+	task func(time.Time)
+	ctx  context.Context
+
+	stopWatchingContext func() bool
+	index               int
+}
+
+type dispatcherTimerHeap []*dispatcherTimer
+
+func (h dispatcherTimerHeap) Len() int { return len(h) }
+
+func (h dispatcherTimerHeap) Less(i, j int) bool { return h[i].deadline.Before(h[j].deadline) }
+
+func (h dispatcherTimerHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *dispatcherTimerHeap) Push(x any) {
+	timer := x.(*dispatcherTimer)
+	timer.index = len(*h)
+	*h = append(*h, timer)
+}
+
+func (h *dispatcherTimerHeap) Pop() any {
+	old := *h
+	n := len(old)
+	timer := old[n-1]
+	old[n-1] = nil
+	timer.index = -1
+	*h = old[:n-1]
+	return timer
+}
+
+// dispatcher keeps all pending deadlines in a min-heap that is served by a
+// single goroutine waiting on one reset-able timer. Due tasks are handed to
+// the executor. The goroutine exits whenever the heap runs empty and is
+// restarted by the next scheduled task.
+type dispatcher struct {
+	executor Executor
+
+	mu      sync.Mutex
+	timers  dispatcherTimerHeap
+	running bool
+	wakeup  chan struct{}
+}
+
+func newDispatcher(executor Executor) *dispatcher {
+	if executor == nil {
+		panic("executor can't be nil")
+	}
+
+	return &dispatcher{
+		executor: executor,
+		timers:   make(dispatcherTimerHeap, 0),
+		wakeup:   make(chan struct{}, 1),
+	}
+}
+
+func (d *dispatcher) performNow(task func(time.Time), ctx context.Context) {
+	scheduledTime := time.Now()
+
+	d.executor.Execute(func() {
+		if ctx.Err() != nil {
+			return
+		}
+		task(scheduledTime)
+	})
+}
+
+func (d *dispatcher) performAfter(task func(time.Time), duration time.Duration, ctx context.Context) {
+	d.add(&dispatcherTimer{
+		deadline: time.Now().Add(duration),
+		task:     task,
+		ctx:      ctx,
+	})
+}
+
+func (d *dispatcher) performRepeatedly(task func(time.Time), until *time.Time, interval time.Duration, ctx context.Context) {
+	if interval <= 0 {
+		panic("interval must be greater than zero")
+	}
+
+	d.add(&dispatcherTimer{
+		deadline: time.Now().Add(interval),
+		interval: interval,
+		until:    until,
+		task:     task,
+		ctx:      ctx,
+	})
+}
+
+func (d *dispatcher) add(timer *dispatcherTimer) {
+	if timer.ctx.Err() != nil {
+		return
+	}
+
+	if timer.until != nil && !timer.deadline.Before(*timer.until) {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.push(timer)
+	timer.stopWatchingContext = context.AfterFunc(timer.ctx, func() { d.remove(timer) })
+}
+
+func (d *dispatcher) push(timer *dispatcherTimer) {
+	heap.Push(&d.timers, timer)
+
+	if !d.running {
+		d.running = true
+		go d.run()
+		return
+	}
+
+	if timer.index == 0 {
+		d.wake()
+	}
+}
+
+func (d *dispatcher) remove(timer *dispatcherTimer) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if timer.index < 0 {
+		return
+	}
+
+	heap.Remove(&d.timers, timer.index)
+	d.wake()
+}
+
+func (d *dispatcher) wake() {
+	select {
+	case d.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+func (d *dispatcher) run() {
+	waitTimer := time.NewTimer(time.Hour)
+	stopTimer(waitTimer)
+
+	for {
+		d.mu.Lock()
+
+		if len(d.timers) == 0 {
+			d.running = false
+			d.mu.Unlock()
+			return
+		}
+
+		next := d.timers[0]
+
+		if wait := time.Until(next.deadline); wait > 0 {
+			d.mu.Unlock()
+
+			waitTimer.Reset(wait)
+			select {
+			case <-waitTimer.C:
+			case <-d.wakeup:
+				stopTimer(waitTimer)
+			}
+
+			continue
+		}
+
+		heap.Pop(&d.timers)
+		deadline := next.deadline
+		finished := !d.reschedule(next)
+		stopWatchingContext := next.stopWatchingContext
+
+		d.mu.Unlock()
+
+		if finished {
+			stopWatchingContext()
+		}
+
+		d.executor.Execute(func() {
+			if next.ctx.Err() != nil {
+				return
+			}
+			next.task(deadline)
+		})
+	}
+}
+
+// reschedule pushes the next occurrence of a repeated timer. Like a
+// time.Ticker it drops occurrences that have already been missed.
+func (d *dispatcher) reschedule(timer *dispatcherTimer) bool {
+	if timer.interval == 0 {
+		return false
+	}
+
+	nextDeadline := timer.deadline.Add(timer.interval)
+	if now := time.Now(); !nextDeadline.After(now) {
+		missed := now.Sub(nextDeadline)/timer.interval + 1
+		nextDeadline = nextDeadline.Add(missed * timer.interval)
+	}
+
+	if timer.until != nil && !nextDeadline.Before(*timer.until) {
+		return false
+	}
+
+	timer.deadline = nextDeadline
+	heap.Push(&d.timers, timer)
+
+	return true
+}
+
+func stopTimer(timer *time.Timer) {
+	if !timer.Stop() {
+		select {
+		case <-timer.C:
+		default:
+		}
+	}
+}
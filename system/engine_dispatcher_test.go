@@ -0,0 +1,219 @@
+package system
+
+import (
+	"container/heap"
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_newDispatcher(t *testing.T) {
+	t.Parallel()
+
+	require.Panics(t, func() {
+		_ = newDispatcher(nil)
+	})
+
+	dispatcherUnderTest := newDispatcher(GoroutineExecutor{})
+	require.NotNil(t, dispatcherUnderTest)
+	require.NotNil(t, dispatcherUnderTest.timers)
+	require.NotNil(t, dispatcherUnderTest.wakeup)
+	require.False(t, dispatcherUnderTest.running)
+}
+
+func Test_dispatcher_performAfter_order(t *testing.T) {
+	t.Parallel()
+
+	dispatcherUnderTest := newDispatcher(GoroutineExecutor{})
+
+	mu := sync.Mutex{}
+	order := make([]int, 0)
+
+	wg := &sync.WaitGroup{}
+	wg.Add(3)
+
+	for _, i := range []int{3, 1, 2} {
+		dispatcherUnderTest.performAfter(func(time.Time) {
+			mu.Lock()
+			order = append(order, i)
+			mu.Unlock()
+			wg.Done()
+		}, time.Duration(i)*10*time.Millisecond, context.Background())
+	}
+
+	wg.Wait()
+	require.Equal(t, []int{1, 2, 3}, order)
+}
+
+func Test_dispatcher_performAfter_cancelled(t *testing.T) {
+	t.Parallel()
+
+	dispatcherUnderTest := newDispatcher(GoroutineExecutor{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	dispatcherUnderTest.performAfter(func(time.Time) { t.Error("cancelled task performed") }, time.Hour, ctx)
+	require.Equal(t, 1, dispatcherUnderTest.pending())
+
+	cancel()
+	require.Eventually(t, func() bool { return dispatcherUnderTest.pending() == 0 }, time.Second, time.Millisecond)
+	require.Eventually(t, func() bool { return !dispatcherUnderTest.isRunning() }, time.Second, time.Millisecond)
+}
+
+func Test_dispatcher_performAfter_earlierDeadlineWakesDispatcher(t *testing.T) {
+	t.Parallel()
+
+	dispatcherUnderTest := newDispatcher(GoroutineExecutor{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dispatcherUnderTest.performAfter(func(time.Time) {}, time.Hour, ctx)
+
+	done := make(chan struct{})
+	dispatcherUnderTest.performAfter(func(time.Time) { close(done) }, time.Millisecond, ctx)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("earlier deadline did not wake the dispatcher")
+	}
+}
+
+func Test_dispatcher_performRepeatedly(t *testing.T) {
+	t.Parallel()
+
+	dispatcherUnderTest := newDispatcher(GoroutineExecutor{})
+
+	require.Panics(t, func() {
+		dispatcherUnderTest.performRepeatedly(func(time.Time) {}, nil, 0, context.Background())
+	})
+
+	mu := sync.Mutex{}
+	performedAt := make([]time.Time, 0)
+
+	until := time.Now().Add(90 * time.Millisecond)
+	dispatcherUnderTest.performRepeatedly(func(time.Time) {
+		mu.Lock()
+		performedAt = append(performedAt, time.Now())
+		mu.Unlock()
+	}, &until, 20*time.Millisecond, context.Background())
+
+	require.Eventually(t, func() bool { return !dispatcherUnderTest.isRunning() }, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	require.Len(t, performedAt, 4)
+	require.True(t, slices.IsSortedFunc(performedAt, func(a, b time.Time) int { return a.Compare(b) }))
+}
+
+func Test_dispatcher_reschedule(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	tests := []struct {
+		name         string
+		timer        *dispatcherTimer
+		want         bool
+		wantDeadline time.Time
+	}{
+		{
+			name:  "single timer",
+			timer: &dispatcherTimer{deadline: now},
+			want:  false,
+		},
+		{
+			name: "next occurrence after until",
+			timer: &dispatcherTimer{
+				deadline: now.Add(time.Hour),
+				interval: time.Hour,
+				until:    ptr(now.Add(90 * time.Minute)),
+			},
+			want: false,
+		},
+		{
+			name: "next occurrence in the future",
+			timer: &dispatcherTimer{
+				deadline: now.Add(time.Hour),
+				interval: time.Hour,
+			},
+			want:         true,
+			wantDeadline: now.Add(2 * time.Hour),
+		},
+		{
+			name: "missed occurrences are dropped",
+			timer: &dispatcherTimer{
+				deadline: now.Add(-150 * time.Minute),
+				interval: time.Hour,
+			},
+			want:         true,
+			wantDeadline: now.Add(30 * time.Minute),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			d := newDispatcher(GoroutineExecutor{})
+
+			got := d.reschedule(tt.timer)
+			require.Equal(t, tt.want, got)
+
+			if tt.want {
+				require.Equal(t, 1, d.timers.Len())
+				require.Equal(t, tt.wantDeadline, tt.timer.deadline)
+			} else {
+				require.Equal(t, 0, d.timers.Len())
+			}
+		})
+	}
+}
+
+func Test_dispatcherTimerHeap(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	timers := make(dispatcherTimerHeap, 0)
+	for _, offset := range []int{5, 1, 4, 2, 3} {
+		heap.Push(&timers, &dispatcherTimer{deadline: now.Add(time.Duration(offset) * time.Second)})
+	}
+
+	for i, timer := range timers {
+		require.Equal(t, i, timer.index)
+	}
+
+	removed := timers[2]
+	heap.Remove(&timers, removed.index)
+	require.Equal(t, -1, removed.index)
+
+	deadlines := make([]time.Time, 0)
+	for timers.Len() > 0 {
+		deadlines = append(deadlines, heap.Pop(&timers).(*dispatcherTimer).deadline)
+	}
+
+	require.Len(t, deadlines, 4)
+	require.True(t, slices.IsSortedFunc(deadlines, func(a, b time.Time) int { return a.Compare(b) }))
+}
+
+func (d *dispatcher) pending() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.timers.Len()
+}
+
+func (d *dispatcher) isRunning() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.running
+}
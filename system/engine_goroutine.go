@@ -0,0 +1,67 @@
+package system
+
+import (
+	"context"
+	"time"
+)
+
+// goroutineEngine starts one goroutine per scheduled task and one ticker per
+// repeated task.
+type goroutineEngine struct{}
+
+func (g goroutineEngine) performNow(task func(time.Time), ctx context.Context) {
+	scheduledTime := time.Now()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+			task(scheduledTime)
+		}
+	}()
+}
+
+func (g goroutineEngine) performAfter(task func(time.Time), duration time.Duration, ctx context.Context) {
+	scheduledTime := time.Now().Add(duration)
+
+	go func() {
+		select {
+		case <-time.After(duration):
+			task(scheduledTime)
+		case <-ctx.Done():
+			return
+		}
+	}()
+}
+
+func (g goroutineEngine) performRepeatedly(task func(time.Time), until *time.Time, interval time.Duration, ctx context.Context) {
+	ticker := time.NewTicker(interval)
+
+	var timer *time.Timer
+	if until != nil {
+		timer = time.NewTimer(time.Until(*until))
+	} else {
+		timer = &time.Timer{}
+	}
+
+	go func() {
+		defer ticker.Stop()
+
+		for {
+			select {
+			case tick := <-ticker.C:
+				// A tick and the timer may be ready at the same time, and
+				// select doesn't prefer either.
+				if ctx.Err() != nil || (until != nil && !tick.Before(*until)) {
+					return
+				}
+				task(tick)
+			case <-timer.C:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,268 @@
+package system
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/metamogul/timing/internal/tick"
+)
+
+const (
+	timingWheelBits   = 8
+	timingWheelSlots  = 1 << timingWheelBits
+	timingWheelMask   = timingWheelSlots - 1
+	timingWheelLevels = 64 / timingWheelBits
+)
+
+type timingWheelTimer struct {
+	deadline time.Time
+	expiry   int64
+	interval time.Duration
+	until    *time.Time
+
+	task func(time.Time)
+	ctx  context.Context
+
+	stopWatchingContext func() bool
+	slot                *list.List
+	element             *list.Element
+}
+
+// timingWheelDue is a timer that expired, together with the deadline it expired
+// for, which rescheduling a repeated timer overwrites.
+type timingWheelDue struct {
+	*timingWheelTimer
+	deadline time.Time
+}
+
+// timingWheel is a hashed hierarchical timing wheel. Deadlines are rounded up
+// to the next multiple of tick since the Unix epoch, which makes inserting and
+// cancelling a timer O(1) regardless of the number of pending timers. A single
+// goroutine advances the wheel once per tick while timers are pending.
+type timingWheel struct {
+	tick     time.Duration
+	executor Executor
+
+	mu      sync.Mutex
+	levels  [timingWheelLevels][timingWheelSlots]*list.List
+	current int64
+	pending int
+	running bool
+}
+
+func newTimingWheel(tick time.Duration, executor Executor) *timingWheel {
+	if tick <= 0 {
+		panic("tick must be greater than zero")
+	}
+
+	if executor == nil {
+		panic("executor can't be nil")
+	}
+
+	w := &timingWheel{
+		tick:     tick,
+		executor: executor,
+	}
+
+	for level := range w.levels {
+		for slot := range w.levels[level] {
+			w.levels[level][slot] = list.New()
+		}
+	}
+
+	return w
+}
+
+func (w *timingWheel) performNow(task func(time.Time), ctx context.Context) {
+	scheduledTime := time.Now()
+
+	w.executor.Execute(func() {
+		if ctx.Err() != nil {
+			return
+		}
+		task(scheduledTime)
+	})
+}
+
+func (w *timingWheel) performAfter(task func(time.Time), duration time.Duration, ctx context.Context) {
+	w.add(&timingWheelTimer{
+		deadline: time.Now().Add(duration),
+		task:     task,
+		ctx:      ctx,
+	})
+}
+
+func (w *timingWheel) performRepeatedly(task func(time.Time), until *time.Time, interval time.Duration, ctx context.Context) {
+	if interval <= 0 {
+		panic("interval must be greater than zero")
+	}
+
+	w.add(&timingWheelTimer{
+		deadline: time.Now().Add(interval),
+		interval: interval,
+		until:    until,
+		task:     task,
+		ctx:      ctx,
+	})
+}
+
+func (w *timingWheel) add(timer *timingWheelTimer) {
+	if timer.ctx.Err() != nil {
+		return
+	}
+
+	if timer.until != nil && !timer.deadline.Before(*timer.until) {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.running {
+		w.current = tick.Of(time.Now(), w.tick)
+		w.running = true
+		go w.run()
+	}
+
+	timer.expiry = tick.Of(tick.RoundUp(timer.deadline, w.tick), w.tick)
+	w.insert(timer, w.current+1)
+	timer.stopWatchingContext = context.AfterFunc(timer.ctx, func() { w.remove(timer) })
+}
+
+// insert puts timer into the slot for its expiry, but no earlier than the
+// given tick.
+func (w *timingWheel) insert(timer *timingWheelTimer, earliest int64) {
+	expiry := max(timer.expiry, earliest)
+
+	level := 0
+	for ; level < timingWheelLevels-1; level++ {
+		shift := timingWheelBits * (level + 1)
+		if expiry>>shift == w.current>>shift {
+			break
+		}
+	}
+
+	timer.slot = w.levels[level][(expiry>>(timingWheelBits*level))&timingWheelMask]
+	timer.element = timer.slot.PushBack(timer)
+	w.pending++
+}
+
+func (w *timingWheel) remove(timer *timingWheelTimer) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if timer.slot == nil {
+		return
+	}
+
+	timer.slot.Remove(timer.element)
+	timer.slot, timer.element = nil, nil
+	w.pending--
+}
+
+func (w *timingWheel) run() {
+	ticker := time.NewTicker(w.tick)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		due, stopped := w.advance(tick.Of(time.Now(), w.tick))
+
+		for _, timer := range due {
+			w.executor.Execute(func() {
+				if timer.ctx.Err() != nil {
+					return
+				}
+				timer.task(timer.deadline)
+			})
+		}
+
+		if stopped {
+			return
+		}
+	}
+}
+
+// advance processes every tick up to and including target and returns the
+// timers that expired on the way.
+func (w *timingWheel) advance(target int64) (due []timingWheelDue, stopped bool) {
+	w.mu.Lock()
+
+	stopWatching := make([]func() bool, 0)
+
+	for w.current < target && w.pending > 0 {
+		w.current++
+
+		for level := timingWheelLevels - 1; level > 0; level-- {
+			if w.current&(1<<(timingWheelBits*level)-1) != 0 {
+				continue
+			}
+
+			w.cascade(w.levels[level][(w.current>>(timingWheelBits*level))&timingWheelMask])
+		}
+
+		slot := w.levels[0][w.current&timingWheelMask]
+		for element := slot.Front(); element != nil; element = slot.Front() {
+			timer := slot.Remove(element).(*timingWheelTimer)
+			timer.slot, timer.element = nil, nil
+			w.pending--
+
+			due = append(due, timingWheelDue{timer, timer.deadline})
+
+			if !w.reschedule(timer) {
+				stopWatching = append(stopWatching, timer.stopWatchingContext)
+			}
+		}
+	}
+
+	w.current = max(w.current, target)
+
+	if w.pending == 0 {
+		w.running = false
+		stopped = true
+	}
+
+	w.mu.Unlock()
+
+	for _, stop := range stopWatching {
+		stop()
+	}
+
+	return due, stopped
+}
+
+// cascade moves the timers of a higher level's slot down. It runs before the
+// current tick's level-0 slot is processed, so timers expiring on the current
+// tick go there.
+func (w *timingWheel) cascade(slot *list.List) {
+	for element := slot.Front(); element != nil; element = slot.Front() {
+		timer := slot.Remove(element).(*timingWheelTimer)
+		w.pending--
+		w.insert(timer, w.current)
+	}
+}
+
+// reschedule inserts the next occurrence of a repeated timer. Like a
+// time.Ticker it drops occurrences that have already been missed.
+func (w *timingWheel) reschedule(timer *timingWheelTimer) bool {
+	if timer.interval == 0 {
+		return false
+	}
+
+	nextDeadline := timer.deadline.Add(timer.interval)
+	if now := time.Now(); !nextDeadline.After(now) {
+		missed := now.Sub(nextDeadline)/timer.interval + 1
+		nextDeadline = nextDeadline.Add(missed * timer.interval)
+	}
+
+	if timer.until != nil && !nextDeadline.Before(*timer.until) {
+		return false
+	}
+
+	timer.deadline = nextDeadline
+	timer.expiry = tick.Of(tick.RoundUp(nextDeadline, w.tick), w.tick)
+	w.insert(timer, w.current+1)
+
+	return true
+}
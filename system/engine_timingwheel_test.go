@@ -0,0 +1,222 @@
+package system
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/metamogul/timing/internal/tick"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_newTimingWheel(t *testing.T) {
+	t.Parallel()
+
+	require.Panics(t, func() {
+		_ = newTimingWheel(0, GoroutineExecutor{})
+	})
+
+	require.Panics(t, func() {
+		_ = newTimingWheel(time.Millisecond, nil)
+	})
+
+	wheelUnderTest := newTimingWheel(time.Millisecond, GoroutineExecutor{})
+	require.NotNil(t, wheelUnderTest)
+	require.Equal(t, time.Millisecond, wheelUnderTest.tick)
+	require.NotNil(t, wheelUnderTest.levels[timingWheelLevels-1][timingWheelSlots-1])
+	require.False(t, wheelUnderTest.running)
+}
+
+func Test_timingWheel_insert(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		current   int64
+		expiry    int64
+		cascaded  bool
+		wantLevel int
+		wantSlot  int
+	}{
+		{
+			name:      "next tick",
+			current:   0,
+			expiry:    1,
+			wantLevel: 0,
+			wantSlot:  1,
+		},
+		{
+			name:      "expiry already passed",
+			current:   10,
+			expiry:    5,
+			wantLevel: 0,
+			wantSlot:  11,
+		},
+		{
+			name:      "cascaded on its expiry",
+			current:   256,
+			expiry:    256,
+			cascaded:  true,
+			wantLevel: 0,
+			wantSlot:  0,
+		},
+		{
+			name:      "end of current block",
+			current:   256,
+			expiry:    511,
+			wantLevel: 0,
+			wantSlot:  255,
+		},
+		{
+			name:      "next block",
+			current:   255,
+			expiry:    256,
+			wantLevel: 1,
+			wantSlot:  1,
+		},
+		{
+			name:      "far future",
+			current:   0,
+			expiry:    3 << 24,
+			wantLevel: 3,
+			wantSlot:  3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := newTimingWheel(time.Millisecond, GoroutineExecutor{})
+			w.current = tt.current
+
+			earliest := w.current + 1
+			if tt.cascaded {
+				earliest = w.current
+			}
+
+			timer := &timingWheelTimer{expiry: tt.expiry}
+			w.insert(timer, earliest)
+
+			require.Same(t, w.levels[tt.wantLevel][tt.wantSlot], timer.slot)
+			require.Equal(t, 1, w.pending)
+		})
+	}
+}
+
+func Test_timingWheel_advance(t *testing.T) {
+	t.Parallel()
+
+	w := newTimingWheel(time.Millisecond, GoroutineExecutor{})
+
+	expiries := []int64{3, 256, 300, 65536, 70000, 70000}
+	for _, expiry := range expiries {
+		w.insert(&timingWheelTimer{expiry: expiry, ctx: context.Background(), stopWatchingContext: func() bool { return true }}, w.current+1)
+	}
+
+	due, stopped := w.advance(2)
+	require.Empty(t, due)
+	require.False(t, stopped)
+
+	due, stopped = w.advance(3)
+	require.Len(t, due, 1)
+	require.Equal(t, int64(3), due[0].expiry)
+	require.False(t, stopped)
+
+	due, stopped = w.advance(255)
+	require.Empty(t, due)
+	require.False(t, stopped)
+
+	due, stopped = w.advance(256)
+	require.Len(t, due, 1)
+	require.Equal(t, int64(256), due[0].expiry)
+	require.False(t, stopped)
+
+	due, stopped = w.advance(299)
+	require.Empty(t, due)
+	require.False(t, stopped)
+
+	due, stopped = w.advance(65535)
+	require.Len(t, due, 1)
+	require.Equal(t, int64(300), due[0].expiry)
+	require.False(t, stopped)
+
+	due, stopped = w.advance(65536)
+	require.Len(t, due, 1)
+	require.Equal(t, int64(65536), due[0].expiry)
+	require.False(t, stopped)
+
+	due, stopped = w.advance(69999)
+	require.Empty(t, due)
+	require.False(t, stopped)
+
+	due, stopped = w.advance(80000)
+	require.Len(t, due, 2)
+	require.Equal(t, int64(70000), due[0].expiry)
+	require.Equal(t, int64(70000), due[1].expiry)
+	require.True(t, stopped)
+	require.Equal(t, int64(80000), w.current)
+}
+
+func Test_timingWheel_remove(t *testing.T) {
+	t.Parallel()
+
+	w := newTimingWheel(time.Millisecond, GoroutineExecutor{})
+
+	timer := &timingWheelTimer{expiry: 1000}
+	w.insert(timer, w.current+1)
+	require.Equal(t, 1, w.pending)
+
+	w.remove(timer)
+	require.Equal(t, 0, w.pending)
+	require.Nil(t, timer.slot)
+
+	w.remove(timer)
+	require.Equal(t, 0, w.pending)
+
+	due, stopped := w.advance(2000)
+	require.Empty(t, due)
+	require.True(t, stopped)
+}
+
+func Test_timingWheel_performAfter_cancelled(t *testing.T) {
+	t.Parallel()
+
+	w := newTimingWheel(time.Millisecond, GoroutineExecutor{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	for range 1000 {
+		w.performAfter(func(time.Time) { t.Error("cancelled task performed") }, time.Hour, ctx)
+	}
+
+	cancel()
+
+	require.Eventually(t, func() bool {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+
+		return !w.running && w.pending == 0
+	}, time.Second, time.Millisecond)
+}
+
+func Test_timingWheel_performAfter_roundsUpDeadline(t *testing.T) {
+	t.Parallel()
+
+	tickLength := 20 * time.Millisecond
+	w := newTimingWheel(tickLength, GoroutineExecutor{})
+
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+
+	var performedAt time.Time
+	scheduledAt := time.Now()
+	w.performAfter(func(time.Time) {
+		performedAt = time.Now()
+		wg.Done()
+	}, time.Millisecond, context.Background())
+
+	wg.Wait()
+	require.False(t, performedAt.Before(tick.RoundUp(scheduledAt.Add(time.Millisecond), tickLength)))
+}
@@ -3,6 +3,10 @@ package system
 import (
 	"context"
 	"github.com/metamogul/timing"
+	"github.com/metamogul/timing/internal/instrument"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -12,52 +16,159 @@ func (s Clock) Now() time.Time {
 	return time.Now()
 }
 
+// EventScheduler performs actions in real time. The zero value starts one
+// goroutine per scheduled action; use NewDispatchingEventScheduler for large
+// numbers of pending actions, or NewTimingWheelEventScheduler for very large
+// numbers of short, mostly cancelled ones.
 type EventScheduler struct {
 	Clock
+
+	// Metrics, if set, is reported to for every action scheduled afterwards.
+	Metrics timing.Metrics
+	// Hooks, if set, are called for every action scheduled afterwards.
+	// Scheduled times are those requested, before any rounding to ticks.
+	Hooks *timing.Hooks
+	// Middleware wraps every action scheduled afterwards, the first one
+	// outermost.
+	Middleware []timing.ActionMiddleware
+
+	engine engine
+
+	// eventIDs numbers the actions performed.
+	eventIDs atomic.Uint64
+}
+
+func NewDispatchingEventScheduler(executor Executor) *EventScheduler {
+	return &EventScheduler{
+		engine: newDispatcher(executor),
+	}
+}
+
+// NewTimingWheelEventScheduler rounds every deadline up to the next multiple
+// of tick since the Unix epoch.
+func NewTimingWheelEventScheduler(tick time.Duration, executor Executor) *EventScheduler {
+	return &EventScheduler{
+		engine: newTimingWheel(tick, executor),
+	}
 }
 
 func (e *EventScheduler) PerformNow(action timing.Action, ctx context.Context) {
-	go func() {
-		select {
-		case <-ctx.Done():
-			return
-		default:
-			action.Perform(newActionContext(ctx, e.Clock))
-		}
-	}()
+	e.getEngine().performNow(e.task(action, e.Now(), 0, nil, ctx), ctx)
 }
 
 func (e *EventScheduler) PerformAfter(action timing.Action, duration time.Duration, ctx context.Context) {
-	go func() {
-		select {
-		case <-time.After(duration):
-			action.Perform(newActionContext(ctx, e.Clock))
-		case <-ctx.Done():
-			return
-		}
-	}()
+	e.getEngine().performAfter(e.task(action, e.Now().Add(duration), 0, nil, ctx), duration, ctx)
 }
 
 func (e *EventScheduler) PerformRepeatedly(action timing.Action, until *time.Time, interval time.Duration, ctx context.Context) {
-	ticker := time.NewTicker(interval)
+	e.getEngine().performRepeatedly(e.task(action, e.Now().Add(interval), interval, until, ctx), until, interval, ctx)
+}
+
+// WithDeadline is context.WithDeadline, so that code written against the
+// simulated schedulers' WithDeadline runs against real time in production.
+func (e *EventScheduler) WithDeadline(ctx context.Context, deadline time.Time) (context.Context, context.CancelFunc) {
+	return context.WithDeadline(ctx, deadline)
+}
 
-	var timer *time.Timer
-	if until != nil {
-		timer = time.NewTimer(until.Sub(e.Now()))
-	} else {
-		timer = &time.Timer{}
+func (e *EventScheduler) WithTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, timeout)
+}
+
+func (e *EventScheduler) getEngine() engine {
+	if e.engine == nil {
+		return goroutineEngine{}
 	}
 
-	go func() {
-		for {
-			select {
-			case <-ticker.C:
-				action.Perform(newActionContext(ctx, e.Clock))
-			case <-timer.C:
-				return
-			case <-ctx.Done():
+	return e.engine
+}
+
+// task returns the task performing action, which is scheduled first for
+// firstTime and then every interval, if given.
+func (e *EventScheduler) task(action timing.Action, firstTime time.Time, interval time.Duration, until *time.Time, ctx context.Context) func(time.Time) {
+	action = timing.Chain(action, slices.Concat(e.Middleware, timing.MiddlewareFrom(ctx))...)
+	job := timing.JobNameFrom(ctx)
+	repeated := interval > 0
+
+	metadata := func(scheduledTime time.Time, occurrence int) timing.EventMetadata {
+		return timing.EventMetadata{ScheduledTime: scheduledTime, JobName: job, Occurrence: occurrence}
+	}
+
+	perform := func(metadata timing.EventMetadata) {
+		action.Perform(newActionContext(ctx, e.Clock, metadata, e))
+	}
+
+	metrics, hooks := e.Metrics, e.Hooks
+	if metrics == nil && hooks == nil {
+		var occurrences atomic.Int64
+
+		return func(scheduledTime time.Time) {
+			performed := metadata(scheduledTime, int(occurrences.Add(1)))
+			performed.EventID = e.eventIDs.Add(1)
+
+			perform(performed)
+		}
+	}
+
+	if metrics != nil {
+		metrics.EventScheduled(job)
+	}
+	instrument.Scheduled(hooks, metadata(firstTime, 1))
+
+	// last and next are the occurrences performed last and due next, as far
+	// as they are known, and occurrence counts the ones performed.
+	var mu sync.Mutex
+	last, next := firstTime, firstTime
+	occurrence := 0
+
+	stopWatchingContext := context.AfterFunc(ctx, func() {
+		if until != nil && !e.Now().Before(*until) {
+			return
+		}
+
+		if metrics != nil {
+			metrics.EventCancelled(job)
+		}
+
+		mu.Lock()
+		cancelled := metadata(next, occurrence+1)
+		mu.Unlock()
+
+		instrument.Cancelled(hooks, cancelled)
+	})
+
+	// The engines don't tell when repetitions end, but they end at until.
+	if repeated && until != nil && hooks != nil {
+		time.AfterFunc(time.Until(*until), func() {
+			if !stopWatchingContext() {
 				return
 			}
+
+			mu.Lock()
+			finished := metadata(last, occurrence)
+			mu.Unlock()
+
+			instrument.GeneratorFinished(hooks, finished)
+		})
+	}
+
+	return func(scheduledTime time.Time) {
+		// A single action whose context got cancelled while it was being
+		// dispatched has already been reported as cancelled.
+		if !repeated && !stopWatchingContext() {
+			return
+		}
+
+		mu.Lock()
+		last, next = scheduledTime, scheduledTime.Add(interval)
+		occurrence++
+		performed := metadata(scheduledTime, occurrence)
+		mu.Unlock()
+
+		performed.EventID = e.eventIDs.Add(1)
+		instrument.Perform(metrics, hooks, e.Clock, performed, func() { perform(performed) })
+
+		if !repeated {
+			instrument.GeneratorFinished(hooks, performed)
 		}
-	}()
+	}
 }
@@ -3,11 +3,236 @@ package system
 import (
 	"context"
 	"github.com/metamogul/timing"
+	"github.com/metamogul/timing/internal/recorder"
+	"github.com/stretchr/testify/require"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
 
+func eventSchedulersUnderTest() map[string]func() *EventScheduler {
+	return map[string]func() *EventScheduler{
+		"goroutines": func() *EventScheduler { return &EventScheduler{Clock: Clock{}} },
+		"dispatcher": func() *EventScheduler { return NewDispatchingEventScheduler(GoroutineExecutor{}) },
+		"timingWheel": func() *EventScheduler {
+			return NewTimingWheelEventScheduler(100*time.Microsecond, GoroutineExecutor{})
+		},
+	}
+}
+
+// engineSchedulersUnderTest returns the schedulers with an engine, which are
+// run through the same cases as the goroutine per timer one.
+func engineSchedulersUnderTest() map[string]func() *EventScheduler {
+	schedulers := eventSchedulersUnderTest()
+	delete(schedulers, "goroutines")
+
+	return schedulers
+}
+
+func TestNewDispatchingEventScheduler(t *testing.T) {
+	t.Parallel()
+
+	eventSchedulerUnderTest := NewDispatchingEventScheduler(GoroutineExecutor{})
+	require.NotNil(t, eventSchedulerUnderTest)
+	require.IsType(t, &dispatcher{}, eventSchedulerUnderTest.engine)
+}
+
+func TestNewTimingWheelEventScheduler(t *testing.T) {
+	t.Parallel()
+
+	eventSchedulerUnderTest := NewTimingWheelEventScheduler(time.Millisecond, GoroutineExecutor{})
+	require.NotNil(t, eventSchedulerUnderTest)
+	require.IsType(t, &timingWheel{}, eventSchedulerUnderTest.engine)
+}
+
+func TestEventScheduler_PerformNow_engines(t *testing.T) {
+	t.Parallel()
+
+	for name, newEventScheduler := range engineSchedulersUnderTest() {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			clock := Clock{}
+
+			wg := &sync.WaitGroup{}
+
+			mockAction := timing.NewMockAction(t)
+			mockAction.EXPECT().
+				Perform(matchActionContext(ctx, clock)).
+				Run(func(timing.ActionContext) { wg.Done() }).
+				Once()
+
+			eventSchedulerUnderTest := newEventScheduler()
+			wg.Add(1)
+			eventSchedulerUnderTest.PerformNow(mockAction, ctx)
+			wg.Wait()
+		})
+	}
+}
+
+func TestEventScheduler_PerformNow_wrapped(t *testing.T) {
+	t.Parallel()
+
+	for name, newEventScheduler := range eventSchedulersUnderTest() {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			calendarScheduler := timing.CalendarEventScheduler{
+				EventScheduler: newEventScheduler(),
+				Calendar:       timing.NewCalendar(nil),
+			}
+
+			seen := make(chan timing.EventScheduler, 1)
+			calendarScheduler.PerformNow(timing.ActionFunc(func(ctx timing.ActionContext) {
+				seen <- ctx.Scheduler()
+			}), context.Background())
+
+			require.Equal(t, calendarScheduler, <-seen)
+		})
+	}
+}
+
+func TestEventScheduler_PerformNow_cancelled_engines(t *testing.T) {
+	t.Parallel()
+
+	for name, newEventScheduler := range engineSchedulersUnderTest() {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			eventSchedulerUnderTest := newEventScheduler()
+			eventSchedulerUnderTest.PerformNow(timing.NewMockAction(t), ctx)
+			time.Sleep(2 * time.Millisecond)
+		})
+	}
+}
+
+func TestEventScheduler_PerformAfter_engines(t *testing.T) {
+	t.Parallel()
+
+	for name, newEventScheduler := range engineSchedulersUnderTest() {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			clock := Clock{}
+
+			wg := &sync.WaitGroup{}
+
+			mockAction := timing.NewMockAction(t)
+			mockAction.EXPECT().
+				Perform(matchActionContext(ctx, clock)).
+				Run(func(timing.ActionContext) { wg.Done() }).
+				Once()
+
+			eventSchedulerUnderTest := newEventScheduler()
+			wg.Add(1)
+			eventSchedulerUnderTest.PerformAfter(mockAction, time.Millisecond, ctx)
+			wg.Wait()
+		})
+	}
+}
+
+func TestEventScheduler_PerformAfter_cancelled_engines(t *testing.T) {
+	t.Parallel()
+
+	for name, newEventScheduler := range engineSchedulersUnderTest() {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			eventSchedulerUnderTest := newEventScheduler()
+			eventSchedulerUnderTest.PerformAfter(timing.NewMockAction(t), time.Millisecond, ctx)
+			time.Sleep(2 * time.Millisecond)
+		})
+	}
+}
+
+func TestEventScheduler_PerformRepeatedly_until_engines(t *testing.T) {
+	t.Parallel()
+
+	for name, newEventScheduler := range engineSchedulersUnderTest() {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			clock := Clock{}
+
+			wg := &sync.WaitGroup{}
+
+			mockAction := timing.NewMockAction(t)
+			mockAction.EXPECT().
+				Perform(matchActionContext(ctx, clock)).
+				Run(func(timing.ActionContext) { wg.Done() }).
+				Twice()
+
+			eventSchedulerUnderTest := newEventScheduler()
+			wg.Add(2)
+			eventSchedulerUnderTest.PerformRepeatedly(mockAction, ptr(clock.Now().Add(25*time.Millisecond)), 10*time.Millisecond, ctx)
+			wg.Wait()
+			time.Sleep(30 * time.Millisecond)
+		})
+	}
+}
+
+func TestEventScheduler_PerformRepeatedly_indefinitely_engines(t *testing.T) {
+	t.Parallel()
+
+	for name, newEventScheduler := range engineSchedulersUnderTest() {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			clock := Clock{}
+
+			wg := &sync.WaitGroup{}
+			calls := atomic.Int32{}
+
+			mockAction := timing.NewMockAction(t)
+			mockAction.EXPECT().
+				Perform(matchActionContext(ctx, clock)).
+				Run(func(timing.ActionContext) {
+					if calls.Add(1) == 2 {
+						cancel()
+						wg.Done()
+					}
+				}).
+				Twice()
+
+			eventSchedulerUnderTest := newEventScheduler()
+			wg.Add(1)
+			eventSchedulerUnderTest.PerformRepeatedly(mockAction, nil, time.Millisecond, ctx)
+			wg.Wait()
+			time.Sleep(3 * time.Millisecond)
+		})
+	}
+}
+
+func TestEventScheduler_PerformRepeatedly_cancelled_engines(t *testing.T) {
+	t.Parallel()
+
+	for name, newEventScheduler := range engineSchedulersUnderTest() {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			clock := Clock{}
+
+			eventSchedulerUnderTest := newEventScheduler()
+			eventSchedulerUnderTest.PerformRepeatedly(timing.NewMockAction(t), ptr(clock.Now().Add(3*time.Millisecond)), time.Millisecond, ctx)
+			time.Sleep(2 * time.Millisecond)
+		})
+	}
+}
+
 func TestEventScheduler_PerformNow(t *testing.T) {
 	t.Parallel()
 
@@ -18,7 +243,7 @@ func TestEventScheduler_PerformNow(t *testing.T) {
 
 	mockAction := timing.NewMockAction(t)
 	mockAction.EXPECT().
-		Perform(newActionContext(ctx, clock)).
+		Perform(matchActionContext(ctx, clock)).
 		Run(func(timing.ActionContext) { wg.Done() }).
 		Once()
 
@@ -50,7 +275,7 @@ func TestEventScheduler_PerformAfter(t *testing.T) {
 
 	mockAction := timing.NewMockAction(t)
 	mockAction.EXPECT().
-		Perform(newActionContext(ctx, clock)).
+		Perform(matchActionContext(ctx, clock)).
 		Run(func(timing.ActionContext) { wg.Done() }).
 		Once()
 
@@ -82,7 +307,7 @@ func TestEventScheduler_PerformRepeatedly_until(t *testing.T) {
 
 	mockAction := timing.NewMockAction(t)
 	mockAction.EXPECT().
-		Perform(newActionContext(ctx, clock)).
+		Perform(matchActionContext(ctx, clock)).
 		Run(func(timing.ActionContext) { wg.Done() }).
 		Twice()
 
@@ -95,16 +320,29 @@ func TestEventScheduler_PerformRepeatedly_until(t *testing.T) {
 func TestEventScheduler_PerformRepeatedly_indefinitely(t *testing.T) {
 	t.Parallel()
 
-	ctx := context.Background()
+	// Cancelling after the second call stops the ticker, which would
+	// otherwise keep performing after the test.
+	ctx, cancel := context.WithCancel(context.Background())
 	clock := Clock{}
 
+	wg := &sync.WaitGroup{}
+	calls := atomic.Int32{}
+
 	mockAction := timing.NewMockAction(t)
 	mockAction.EXPECT().
-		Perform(newActionContext(ctx, clock)).
+		Perform(matchActionContext(ctx, clock)).
+		Run(func(timing.ActionContext) {
+			if calls.Add(1) == 2 {
+				cancel()
+				wg.Done()
+			}
+		}).
 		Twice()
 
 	eventSchedulerUnderTest := &EventScheduler{Clock: Clock{}}
+	wg.Add(1)
 	eventSchedulerUnderTest.PerformRepeatedly(mockAction, nil, time.Millisecond, ctx)
+	wg.Wait()
 	time.Sleep(3 * time.Millisecond)
 }
 
@@ -119,3 +357,303 @@ func TestEventScheduler_PerformRepeatedly_cancelled(t *testing.T) {
 	eventSchedulerUnderTest.PerformRepeatedly(timing.NewMockAction(t), ptr(clock.Now().Add(3*time.Millisecond)), time.Millisecond, ctx)
 	time.Sleep(2 * time.Millisecond)
 }
+
+func BenchmarkEventScheduler_PerformAfter_pending(b *testing.B) {
+	for name, newEventScheduler := range eventSchedulersUnderTest() {
+		b.Run(name, func(b *testing.B) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			eventScheduler := newEventScheduler()
+			action := timing.ActionFunc(func(timing.ActionContext) {})
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for range b.N {
+				eventScheduler.PerformAfter(action, time.Hour, ctx)
+			}
+		})
+	}
+}
+
+func BenchmarkEventScheduler_PerformAfter_firing(b *testing.B) {
+	for name, newEventScheduler := range eventSchedulersUnderTest() {
+		b.Run(name, func(b *testing.B) {
+			eventScheduler := newEventScheduler()
+
+			wg := &sync.WaitGroup{}
+			action := timing.ActionFunc(func(timing.ActionContext) { wg.Done() })
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			wg.Add(b.N)
+			for range b.N {
+				eventScheduler.PerformAfter(action, time.Millisecond, context.Background())
+			}
+			wg.Wait()
+		})
+	}
+}
+
+func TestEventScheduler_Metrics(t *testing.T) {
+	t.Parallel()
+
+	for name, newEventScheduler := range eventSchedulersUnderTest() {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			metrics := recorder.NewMetrics()
+
+			eventSchedulerUnderTest := newEventScheduler()
+			eventSchedulerUnderTest.Metrics = metrics
+
+			cancelledCtx, cancel := context.WithCancel(timing.WithJobName(context.Background(), "cancelled"))
+
+			eventSchedulerUnderTest.PerformAfter(timing.ActionFunc(func(timing.ActionContext) {}), 5*time.Millisecond, timing.WithJobName(context.Background(), "performed"))
+			eventSchedulerUnderTest.PerformAfter(timing.ActionFunc(func(timing.ActionContext) { t.Error("cancelled action performed") }), time.Hour, cancelledCtx)
+			cancel()
+
+			require.Eventually(t, func() bool {
+				return metrics.Count(metrics.Finished, "performed") == 1
+			}, time.Second, time.Millisecond)
+
+			require.Equal(t, 1, metrics.Count(metrics.Scheduled, "performed"))
+			require.Equal(t, 1, metrics.Count(metrics.Started, "performed"))
+			require.Equal(t, 0, metrics.Count(metrics.Cancelled, "performed"))
+			require.Equal(t, 1, metrics.Count(metrics.Scheduled, "cancelled"))
+			require.Equal(t, 1, metrics.Count(metrics.Cancelled, "cancelled"))
+			require.Equal(t, 0, metrics.Count(metrics.Started, "cancelled"))
+
+			metrics.Lock()
+			defer metrics.Unlock()
+			require.GreaterOrEqual(t, metrics.Lateness[0], time.Duration(0))
+		})
+	}
+}
+
+func TestEventScheduler_Metrics_repeatedUntil(t *testing.T) {
+	t.Parallel()
+
+	metrics := recorder.NewMetrics()
+
+	eventSchedulerUnderTest := NewDispatchingEventScheduler(GoroutineExecutor{})
+	eventSchedulerUnderTest.Metrics = metrics
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	until := time.Now().Add(25 * time.Millisecond)
+	eventSchedulerUnderTest.PerformRepeatedly(timing.ActionFunc(func(timing.ActionContext) {}), &until, 10*time.Millisecond, ctx)
+
+	require.Eventually(t, func() bool {
+		return metrics.Count(metrics.Finished, "") == 2
+	}, time.Second, time.Millisecond)
+
+	time.Sleep(time.Until(until))
+	cancel()
+
+	require.Equal(t, 1, metrics.Count(metrics.Scheduled, ""))
+	require.Equal(t, 0, metrics.Count(metrics.Cancelled, ""))
+}
+
+func TestEventScheduler_Hooks(t *testing.T) {
+	t.Parallel()
+
+	for name, newEventScheduler := range eventSchedulersUnderTest() {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			hooks := &recordingHooks{}
+
+			eventSchedulerUnderTest := newEventScheduler()
+			eventSchedulerUnderTest.Hooks = hooks.hooks()
+
+			cancelledCtx, cancel := context.WithCancel(timing.WithJobName(context.Background(), "cancelled"))
+
+			scheduledAt := time.Now()
+			eventSchedulerUnderTest.PerformAfter(timing.ActionFunc(func(timing.ActionContext) {}), 5*time.Millisecond, timing.WithJobName(context.Background(), "performed"))
+			eventSchedulerUnderTest.PerformAfter(timing.ActionFunc(func(timing.ActionContext) { t.Error("cancelled action performed") }), time.Hour, cancelledCtx)
+			cancel()
+
+			require.Eventually(t, func() bool {
+				return slices.Contains(hooks.recorded(), "finished performed")
+			}, time.Second, time.Millisecond)
+
+			require.ElementsMatch(t, []string{
+				"scheduled performed",
+				"scheduled cancelled",
+				"cancelled cancelled",
+				"before performed",
+				"after performed",
+				"finished performed",
+			}, hooks.recorded())
+
+			scheduledTime := hooks.metadataOf("scheduled performed").ScheduledTime
+			require.WithinRange(t, scheduledTime, scheduledAt.Add(5*time.Millisecond), time.Now())
+			require.WithinDuration(t, scheduledTime, hooks.metadataOf("before performed").ScheduledTime, time.Millisecond)
+			require.WithinDuration(t, scheduledAt.Add(time.Hour), hooks.metadataOf("cancelled cancelled").ScheduledTime, time.Second)
+		})
+	}
+}
+
+func TestEventScheduler_Hooks_repeatedUntil(t *testing.T) {
+	t.Parallel()
+
+	hooks := &recordingHooks{}
+
+	eventSchedulerUnderTest := NewDispatchingEventScheduler(GoroutineExecutor{})
+	eventSchedulerUnderTest.Hooks = hooks.hooks()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	until := time.Now().Add(25 * time.Millisecond)
+	eventSchedulerUnderTest.PerformRepeatedly(timing.ActionFunc(func(timing.ActionContext) {}), &until, 10*time.Millisecond, ctx)
+
+	require.Eventually(t, func() bool {
+		return slices.Contains(hooks.recorded(), "finished ")
+	}, time.Second, time.Millisecond)
+
+	require.Equal(t, []string{
+		"scheduled ",
+		"before ",
+		"after ",
+		"before ",
+		"after ",
+		"finished ",
+	}, hooks.recorded())
+	require.Equal(t, hooks.metadataOf("before ").ScheduledTime, hooks.metadataOf("finished ").ScheduledTime)
+
+	cancel()
+	require.NotContains(t, hooks.recorded(), "cancelled ")
+}
+
+func TestEventScheduler_Middleware(t *testing.T) {
+	t.Parallel()
+
+	for name, newEventScheduler := range eventSchedulersUnderTest() {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			mu := sync.Mutex{}
+			calls := make([]string, 0)
+			record := func(call string) {
+				mu.Lock()
+				defer mu.Unlock()
+				calls = append(calls, call)
+			}
+
+			middleware := func(name string) timing.ActionMiddleware {
+				return func(action timing.Action) timing.Action {
+					return timing.ActionFunc(func(ctx timing.ActionContext) {
+						record(name + ":" + ctx.Metadata().JobName)
+						action.Perform(ctx)
+					})
+				}
+			}
+
+			eventSchedulerUnderTest := newEventScheduler()
+			eventSchedulerUnderTest.Middleware = []timing.ActionMiddleware{middleware("scheduler")}
+
+			ctx := timing.WithMiddleware(timing.WithJobName(context.Background(), "job"), middleware("job"))
+
+			scheduledAt := time.Now()
+			done := make(chan timing.EventMetadata)
+			eventSchedulerUnderTest.PerformAfter(timing.ActionFunc(func(ctx timing.ActionContext) {
+				record("action")
+				done <- ctx.Metadata()
+			}), 5*time.Millisecond, ctx)
+
+			metadata := <-done
+
+			require.Equal(t, "job", metadata.JobName)
+			require.False(t, metadata.ScheduledTime.Before(scheduledAt.Add(5*time.Millisecond)))
+
+			mu.Lock()
+			defer mu.Unlock()
+			require.Equal(t, []string{"scheduler:job", "job:job", "action"}, calls)
+		})
+	}
+}
+
+func TestEventScheduler_ActionContext(t *testing.T) {
+	t.Parallel()
+
+	for name, newEventScheduler := range eventSchedulersUnderTest() {
+		for _, instrumented := range []bool{false, true} {
+			if instrumented {
+				name += ", instrumented"
+			}
+
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				eventSchedulerUnderTest := newEventScheduler()
+				if instrumented {
+					eventSchedulerUnderTest.Hooks = &timing.Hooks{}
+				}
+
+				ctx, cancel := context.WithCancel(timing.WithJobName(context.Background(), "job"))
+				defer cancel()
+
+				performed := make(chan timing.EventMetadata, 10)
+				eventSchedulerUnderTest.PerformRepeatedly(timing.ActionFunc(func(ctx timing.ActionContext) {
+					require.Same(t, eventSchedulerUnderTest, ctx.Scheduler())
+					require.Equal(t, ctx.Metadata().ScheduledTime, ctx.ScheduledTime())
+
+					performed <- timing.EventMetadata{JobName: ctx.JobName(), EventID: ctx.EventID(), Occurrence: ctx.Occurrence()}
+				}), nil, 2*time.Millisecond, ctx)
+
+				got := []timing.EventMetadata{<-performed, <-performed, <-performed}
+				cancel()
+
+				require.ElementsMatch(t, []timing.EventMetadata{
+					{JobName: "job", EventID: 1, Occurrence: 1},
+					{JobName: "job", EventID: 2, Occurrence: 2},
+					{JobName: "job", EventID: 3, Occurrence: 3},
+				}, got)
+			})
+		}
+	}
+}
+
+func TestEventScheduler_ClockFrom(t *testing.T) {
+	t.Parallel()
+
+	for name, newEventScheduler := range eventSchedulersUnderTest() {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			clock := make(chan timing.Clock)
+			newEventScheduler().PerformNow(timing.ActionFunc(func(ctx timing.ActionContext) {
+				derived, cancel := context.WithCancel(ctx)
+				defer cancel()
+
+				clock <- timing.ClockFrom(derived)
+			}), context.Background())
+
+			require.Equal(t, Clock{}, <-clock)
+		})
+	}
+}
+
+func TestEventScheduler_WithTimeout(t *testing.T) {
+	t.Parallel()
+
+	var eventSchedulerUnderTest timing.DeadlineScheduler = &EventScheduler{}
+
+	ctx, cancel := eventSchedulerUnderTest.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+
+	<-ctx.Done()
+	require.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+
+	deadline := time.Now().Add(time.Hour)
+	ctx, cancel = eventSchedulerUnderTest.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	gotDeadline, ok := ctx.Deadline()
+	require.True(t, ok)
+	require.Equal(t, deadline, gotDeadline)
+}
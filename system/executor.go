@@ -0,0 +1,60 @@
+package system
+
+import (
+	"sync"
+)
+
+type Executor interface {
+	Execute(task func())
+}
+
+type GoroutineExecutor struct{}
+
+func (g GoroutineExecutor) Execute(task func()) {
+	go task()
+}
+
+// WorkerPoolExecutor runs tasks on a fixed number of worker goroutines.
+// Execute blocks while all workers are busy and the queue is full.
+type WorkerPoolExecutor struct {
+	tasks chan func()
+	wg    sync.WaitGroup
+}
+
+func NewWorkerPoolExecutor(workers int, queueSize int) *WorkerPoolExecutor {
+	if workers <= 0 {
+		panic("workers must be greater than zero")
+	}
+
+	if queueSize < 0 {
+		panic("queueSize can't be negative")
+	}
+
+	w := &WorkerPoolExecutor{
+		tasks: make(chan func(), queueSize),
+	}
+
+	w.wg.Add(workers)
+	for range workers {
+		go func() {
+			defer w.wg.Done()
+
+			for task := range w.tasks {
+				task()
+			}
+		}()
+	}
+
+	return w
+}
+
+func (w *WorkerPoolExecutor) Execute(task func()) {
+	w.tasks <- task
+}
+
+// Close waits for all queued tasks to finish. Execute must not be called
+// after Close.
+func (w *WorkerPoolExecutor) Close() {
+	close(w.tasks)
+	w.wg.Wait()
+}
@@ -0,0 +1,81 @@
+package system
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoroutineExecutor_Execute(t *testing.T) {
+	t.Parallel()
+
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+
+	GoroutineExecutor{}.Execute(wg.Done)
+
+	wg.Wait()
+}
+
+func TestNewWorkerPoolExecutor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		workers      int
+		queueSize    int
+		requirePanic bool
+	}{
+		{
+			name:         "no workers",
+			workers:      0,
+			queueSize:    0,
+			requirePanic: true,
+		},
+		{
+			name:         "negative queue size",
+			workers:      1,
+			queueSize:    -1,
+			requirePanic: true,
+		},
+		{
+			name:      "success",
+			workers:   2,
+			queueSize: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if tt.requirePanic {
+				require.Panics(t, func() {
+					_ = NewWorkerPoolExecutor(tt.workers, tt.queueSize)
+				})
+				return
+			}
+
+			executorUnderTest := NewWorkerPoolExecutor(tt.workers, tt.queueSize)
+			require.NotNil(t, executorUnderTest)
+			require.Equal(t, tt.queueSize, cap(executorUnderTest.tasks))
+			executorUnderTest.Close()
+		})
+	}
+}
+
+func TestWorkerPoolExecutor_Execute(t *testing.T) {
+	t.Parallel()
+
+	executorUnderTest := NewWorkerPoolExecutor(4, 0)
+
+	counter := atomic.Int32{}
+	for range 100 {
+		executorUnderTest.Execute(func() { counter.Add(1) })
+	}
+
+	executorUnderTest.Close()
+	require.Equal(t, int32(100), counter.Load())
+}
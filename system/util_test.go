@@ -1,5 +1,69 @@
 package system
 
+import (
+	"context"
+	"github.com/metamogul/timing"
+	"github.com/stretchr/testify/mock"
+	"slices"
+	"sync"
+)
+
 func ptr[T any](t T) *T {
 	return &t
 }
+
+// matchActionContext matches action contexts for ctx and clock, whatever
+// their event metadata.
+func matchActionContext(ctx context.Context, clock timing.Clock) any {
+	return mock.MatchedBy(func(actionContext *actionContext) bool {
+		return actionContext.Context == ctx && actionContext.clock == clock
+	})
+}
+
+// recordingHooks records the hooks called, as the hook's name and the job,
+// along with the metadata.
+type recordingHooks struct {
+	mu       sync.Mutex
+	calls    []string
+	metadata map[string]timing.EventMetadata
+}
+
+func (r *recordingHooks) hooks() *timing.Hooks {
+	record := func(hook string) func(timing.EventMetadata) {
+		return func(metadata timing.EventMetadata) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+
+			call := hook + " " + metadata.JobName
+			r.calls = append(r.calls, call)
+
+			if r.metadata == nil {
+				r.metadata = make(map[string]timing.EventMetadata)
+			}
+			r.metadata[call] = metadata
+		}
+	}
+
+	return &timing.Hooks{
+		OnScheduled:         record("scheduled"),
+		OnBeforePerform:     record("before"),
+		OnAfterPerform:      record("after"),
+		OnCancelled:         record("cancelled"),
+		OnGeneratorFinished: record("finished"),
+		OnClockAdvanced:     record("advanced"),
+	}
+}
+
+func (r *recordingHooks) recorded() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Clone(r.calls)
+}
+
+func (r *recordingHooks) metadataOf(call string) timing.EventMetadata {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.metadata[call]
+}
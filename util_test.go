@@ -0,0 +1,66 @@
+package timing
+
+import (
+	"context"
+	"time"
+)
+
+func ptr[T any](t T) *T {
+	return &t
+}
+
+// immediateEventScheduler performs every action once, right away.
+type immediateEventScheduler struct {
+	performed []Action
+}
+
+func (i *immediateEventScheduler) Now() time.Time { return time.Time{} }
+
+func (i *immediateEventScheduler) PerformNow(action Action, ctx context.Context) {
+	i.performed = append(i.performed, action)
+	action.Perform(nil)
+}
+
+func (i *immediateEventScheduler) PerformAfter(action Action, _ time.Duration, ctx context.Context) {
+	i.PerformNow(action, ctx)
+}
+
+func (i *immediateEventScheduler) PerformRepeatedly(action Action, _ *time.Time, _ time.Duration, ctx context.Context) {
+	i.PerformNow(action, ctx)
+}
+
+type pendingAction struct {
+	action   Action
+	duration time.Duration
+	ctx      context.Context
+}
+
+// pendingEventScheduler collects actions until they are performed by fire.
+type pendingEventScheduler struct {
+	now     time.Time
+	pending []pendingAction
+}
+
+func (p *pendingEventScheduler) Now() time.Time { return p.now }
+
+func (p *pendingEventScheduler) PerformNow(action Action, ctx context.Context) {
+	p.PerformAfter(action, 0, ctx)
+}
+
+func (p *pendingEventScheduler) PerformAfter(action Action, duration time.Duration, ctx context.Context) {
+	p.pending = append(p.pending, pendingAction{action, duration, ctx})
+}
+
+func (p *pendingEventScheduler) PerformRepeatedly(action Action, _ *time.Time, interval time.Duration, ctx context.Context) {
+	p.PerformAfter(action, interval, ctx)
+}
+
+func (p *pendingEventScheduler) fire() {
+	for _, pending := range p.pending {
+		if pending.ctx.Err() == nil {
+			pending.action.Perform(nil)
+		}
+	}
+
+	p.pending = nil
+}